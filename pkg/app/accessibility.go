@@ -0,0 +1,526 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// AccessibilityRule represents an accessibility rule checked by
+// TestAccessibility.
+type AccessibilityRule string
+
+const (
+	// ImageAltRule requires images to have an alt attribute or an accessible
+	// name. Decorative images can be marked with the presentation role.
+	ImageAltRule AccessibilityRule = "image-alt"
+
+	// InputLabelRule requires form fields to have a label: an enclosing or
+	// associated label element, an aria-label, an aria-labelledby referring to
+	// an existing element, or a title.
+	InputLabelRule AccessibilityRule = "input-label"
+
+	// ARIARoleRule requires role attributes to contain valid non-abstract WAI-ARIA
+	// roles.
+	ARIARoleRule AccessibilityRule = "aria-role"
+
+	// HeadingOrderRule requires heading levels to increase one by one.
+	HeadingOrderRule AccessibilityRule = "heading-order"
+
+	// DocumentLangRule requires the html element of a document to have a lang
+	// attribute.
+	DocumentLangRule AccessibilityRule = "document-lang"
+)
+
+// AccessibilityIssue describes an element that breaks an accessibility rule.
+type AccessibilityIssue struct {
+	// The broken rule.
+	Rule AccessibilityRule
+
+	// The path of the element from the root, made of tag names and sibling
+	// positions, such as "div > ul > li:nth-child(2) > img".
+	Path string
+
+	// The opening tag of the element, without its content.
+	Element string
+
+	// The description of the issue.
+	Message string
+}
+
+func (i AccessibilityIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s at %s", i.Rule, i.Message, i.Element, i.Path)
+}
+
+// AccessibilityError is the error returned by TestAccessibility when
+// accessibility issues are found.
+type AccessibilityError struct {
+	Issues []AccessibilityIssue
+}
+
+func (e AccessibilityError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v accessibility issue(s) found:", len(e.Issues))
+	for _, issue := range e.Issues {
+		b.WriteString("\n  - ")
+		b.WriteString(issue.String())
+	}
+	return b.String()
+}
+
+// Has reports whether an issue breaks the given rule.
+func (e AccessibilityError) Has(rule AccessibilityRule) bool {
+	for _, issue := range e.Issues {
+		if issue.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// TestAccessibility checks the HTML rendered by the given UI element against
+// common accessibility rules: images without alt text, form fields without
+// label, invalid ARIA roles, skipped heading levels and documents without
+// language. It returns an AccessibilityError that lists the issues found, or
+// nil when there is none.
+//
+// The element can be a component loaded in a test engine or an element that is
+// not mounted. The document language is checked with TestAccessibilityHTML,
+// such as on a page served by a Handler.
+//
+// Example:
+//
+//	compo := &hello{}
+//	e := app.NewTestEngine()
+//	e.Load(compo)
+//	e.ConsumeAll()
+//
+//	err := app.TestAccessibility(compo)
+//	// err == nil when the rendered component has no issue.
+func TestAccessibility(v UI) error {
+	return TestAccessibilityHTML(HTMLString(v))
+}
+
+// TestAccessibilityHTML checks the given HTML against the rules described in
+// TestAccessibility. The document language is only checked when the HTML
+// starts with a doctype or an html element.
+func TestAccessibilityHTML(s string) error {
+	var roots []*html.Node
+	if isHTMLDocument(s) {
+		doc, err := html.Parse(strings.NewReader(s))
+		if err != nil {
+			return err
+		}
+		roots = []*html.Node{doc}
+	} else {
+		nodes, err := html.ParseFragment(strings.NewReader(s), &html.Node{
+			Type:     html.ElementNode,
+			Data:     "body",
+			DataAtom: atom.Body,
+		})
+		if err != nil {
+			return err
+		}
+		roots = nodes
+	}
+
+	a := newAccessibilityAuditor(roots)
+	for _, n := range roots {
+		a.audit(n)
+	}
+
+	if len(a.issues) == 0 {
+		return nil
+	}
+	return AccessibilityError{Issues: a.issues}
+}
+
+func isHTMLDocument(s string) bool {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return strings.HasPrefix(s, "<!doctype") || strings.HasPrefix(s, "<html")
+}
+
+type accessibilityAuditor struct {
+	ids          map[string]bool
+	labelTargets map[string]bool
+	lastHeading  int
+	issues       []AccessibilityIssue
+}
+
+func newAccessibilityAuditor(roots []*html.Node) *accessibilityAuditor {
+	a := &accessibilityAuditor{
+		ids:          make(map[string]bool),
+		labelTargets: make(map[string]bool),
+	}
+
+	var index func(*html.Node)
+	index = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if id := nodeAttr(n, "id"); id != "" {
+				a.ids[id] = true
+			}
+			if n.DataAtom == atom.Label {
+				if target := nodeAttr(n, "for"); target != "" {
+					a.labelTargets[target] = true
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			index(c)
+		}
+	}
+	for _, n := range roots {
+		index(n)
+	}
+	return a
+}
+
+func (a *accessibilityAuditor) audit(n *html.Node) {
+	if n.Type == html.ElementNode {
+		a.auditDocumentLang(n)
+		a.auditImageAlt(n)
+		a.auditInputLabel(n)
+		a.auditARIARole(n)
+		a.auditHeadingOrder(n)
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		a.audit(c)
+	}
+}
+
+func (a *accessibilityAuditor) report(n *html.Node, rule AccessibilityRule, format string, v ...any) {
+	a.issues = append(a.issues, AccessibilityIssue{
+		Rule:    rule,
+		Path:    nodePath(n),
+		Element: nodeOpeningTag(n),
+		Message: fmt.Sprintf(format, v...),
+	})
+}
+
+func (a *accessibilityAuditor) auditDocumentLang(n *html.Node) {
+	if n.DataAtom == atom.Html && strings.TrimSpace(nodeAttr(n, "lang")) == "" {
+		a.report(n, DocumentLangRule, "document has no lang attribute")
+	}
+}
+
+func (a *accessibilityAuditor) auditImageAlt(n *html.Node) {
+	isImage := n.DataAtom == atom.Img ||
+		(n.DataAtom == atom.Input && strings.EqualFold(nodeAttr(n, "type"), "image"))
+	if !isImage || hiddenFromAccessibility(n) {
+		return
+	}
+
+	switch strings.TrimSpace(nodeAttr(n, "role")) {
+	case "presentation", "none":
+		return
+	}
+
+	if _, ok := nodeAttrOK(n, "alt"); !ok && !a.hasAccessibleName(n) {
+		a.report(n, ImageAltRule, "image has no alt attribute")
+	}
+}
+
+func (a *accessibilityAuditor) auditInputLabel(n *html.Node) {
+	switch n.DataAtom {
+	case atom.Input:
+		switch strings.ToLower(nodeAttr(n, "type")) {
+		case "hidden", "submit", "reset", "button", "image":
+			return
+		}
+
+	case atom.Select, atom.Textarea:
+
+	default:
+		return
+	}
+
+	if hiddenFromAccessibility(n) || a.hasAccessibleName(n) {
+		return
+	}
+	if id := nodeAttr(n, "id"); id != "" && a.labelTargets[id] {
+		return
+	}
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode && p.DataAtom == atom.Label {
+			return
+		}
+	}
+	a.report(n, InputLabelRule, "form field has no label")
+}
+
+func (a *accessibilityAuditor) auditARIARole(n *html.Node) {
+	role, ok := nodeAttrOK(n, "role")
+	if !ok {
+		return
+	}
+
+	roles := strings.Fields(strings.ToLower(role))
+	if len(roles) == 0 {
+		a.report(n, ARIARoleRule, "role attribute is empty")
+		return
+	}
+	for _, r := range roles {
+		if !ariaRoles[r] {
+			a.report(n, ARIARoleRule, "%q is not a valid ARIA role", r)
+		}
+	}
+}
+
+func (a *accessibilityAuditor) auditHeadingOrder(n *html.Node) {
+	level := headingLevel(n)
+	if level == 0 || hiddenFromAccessibility(n) {
+		return
+	}
+
+	if a.lastHeading != 0 && level > a.lastHeading+1 {
+		a.report(n, HeadingOrderRule, "heading level %v follows heading level %v", level, a.lastHeading)
+	}
+	a.lastHeading = level
+}
+
+// hasAccessibleName reports whether the given element is named with an
+// aria-label, an aria-labelledby referring to an existing element, or a title.
+func (a *accessibilityAuditor) hasAccessibleName(n *html.Node) bool {
+	if strings.TrimSpace(nodeAttr(n, "aria-label")) != "" ||
+		strings.TrimSpace(nodeAttr(n, "title")) != "" {
+		return true
+	}
+
+	for _, id := range strings.Fields(nodeAttr(n, "aria-labelledby")) {
+		if a.ids[id] {
+			return true
+		}
+	}
+	return false
+}
+
+func headingLevel(n *html.Node) int {
+	switch n.DataAtom {
+	case atom.H1:
+		return 1
+	case atom.H2:
+		return 2
+	case atom.H3:
+		return 3
+	case atom.H4:
+		return 4
+	case atom.H5:
+		return 5
+	case atom.H6:
+		return 6
+	}
+
+	if strings.TrimSpace(nodeAttr(n, "role")) != "heading" {
+		return 0
+	}
+
+	var level int
+	if _, err := fmt.Sscan(nodeAttr(n, "aria-level"), &level); err != nil || level < 1 {
+		return 2
+	}
+	return level
+}
+
+// hiddenFromAccessibility reports whether the given element or one of its
+// ancestors is removed from the accessibility tree.
+func hiddenFromAccessibility(n *html.Node) bool {
+	for ; n != nil; n = n.Parent {
+		if n.Type != html.ElementNode {
+			continue
+		}
+
+		// Attributes set to true are encoded without value.
+		if v, ok := nodeAttrOK(n, "aria-hidden"); ok && (v == "" || strings.EqualFold(v, "true")) {
+			return true
+		}
+		if _, ok := nodeAttrOK(n, "hidden"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeAttr(n *html.Node, name string) string {
+	v, _ := nodeAttrOK(n, name)
+	return v
+}
+
+func nodeAttrOK(n *html.Node, name string) (string, bool) {
+	for _, attr := range n.Attr {
+		if attr.Namespace == "" && attr.Key == name {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+func nodePath(n *html.Node) string {
+	var path []string
+	for ; n != nil && n.Type == html.ElementNode; n = n.Parent {
+		position := 1
+		for s := n.PrevSibling; s != nil; s = s.PrevSibling {
+			if s.Type == html.ElementNode {
+				position++
+			}
+		}
+
+		step := n.Data
+		if position > 1 {
+			step += fmt.Sprintf(":nth-child(%v)", position)
+		}
+		path = append(path, step)
+	}
+
+	slices.Reverse(path)
+	return strings.Join(path, " > ")
+}
+
+func nodeOpeningTag(n *html.Node) string {
+	var b strings.Builder
+	b.WriteString("<")
+	b.WriteString(n.Data)
+	for _, attr := range n.Attr {
+		fmt.Fprintf(&b, " %s=%q", attr.Key, attr.Val)
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+// The WAI-ARIA 1.2, Graphics ARIA and DPUB-ARIA roles that can be used in
+// role attributes.
+var ariaRoles = map[string]bool{
+	"alert":               true,
+	"alertdialog":         true,
+	"application":         true,
+	"article":             true,
+	"banner":              true,
+	"blockquote":          true,
+	"button":              true,
+	"caption":             true,
+	"cell":                true,
+	"checkbox":            true,
+	"code":                true,
+	"columnheader":        true,
+	"combobox":            true,
+	"comment":             true,
+	"complementary":       true,
+	"contentinfo":         true,
+	"definition":          true,
+	"deletion":            true,
+	"dialog":              true,
+	"directory":           true,
+	"document":            true,
+	"emphasis":            true,
+	"feed":                true,
+	"figure":              true,
+	"form":                true,
+	"generic":             true,
+	"grid":                true,
+	"gridcell":            true,
+	"group":               true,
+	"heading":             true,
+	"image":               true,
+	"img":                 true,
+	"insertion":           true,
+	"link":                true,
+	"list":                true,
+	"listbox":             true,
+	"listitem":            true,
+	"log":                 true,
+	"main":                true,
+	"mark":                true,
+	"marquee":             true,
+	"math":                true,
+	"menu":                true,
+	"menubar":             true,
+	"menuitem":            true,
+	"menuitemcheckbox":    true,
+	"menuitemradio":       true,
+	"meter":               true,
+	"navigation":          true,
+	"none":                true,
+	"note":                true,
+	"option":              true,
+	"paragraph":           true,
+	"presentation":        true,
+	"progressbar":         true,
+	"radio":               true,
+	"radiogroup":          true,
+	"region":              true,
+	"row":                 true,
+	"rowgroup":            true,
+	"rowheader":           true,
+	"scrollbar":           true,
+	"search":              true,
+	"searchbox":           true,
+	"separator":           true,
+	"slider":              true,
+	"spinbutton":          true,
+	"status":              true,
+	"strong":              true,
+	"subscript":           true,
+	"suggestion":          true,
+	"superscript":         true,
+	"switch":              true,
+	"tab":                 true,
+	"table":               true,
+	"tablist":             true,
+	"tabpanel":            true,
+	"term":                true,
+	"textbox":             true,
+	"time":                true,
+	"timer":               true,
+	"toolbar":             true,
+	"tooltip":             true,
+	"tree":                true,
+	"treegrid":            true,
+	"treeitem":            true,
+	"graphics-document":   true,
+	"graphics-object":     true,
+	"graphics-symbol":     true,
+	"doc-abstract":        true,
+	"doc-acknowledgments": true,
+	"doc-afterword":       true,
+	"doc-appendix":        true,
+	"doc-backlink":        true,
+	"doc-biblioentry":     true,
+	"doc-bibliography":    true,
+	"doc-biblioref":       true,
+	"doc-chapter":         true,
+	"doc-colophon":        true,
+	"doc-conclusion":      true,
+	"doc-cover":           true,
+	"doc-credit":          true,
+	"doc-credits":         true,
+	"doc-dedication":      true,
+	"doc-endnote":         true,
+	"doc-endnotes":        true,
+	"doc-epigraph":        true,
+	"doc-epilogue":        true,
+	"doc-errata":          true,
+	"doc-example":         true,
+	"doc-footnote":        true,
+	"doc-foreword":        true,
+	"doc-glossary":        true,
+	"doc-glossref":        true,
+	"doc-index":           true,
+	"doc-introduction":    true,
+	"doc-noteref":         true,
+	"doc-notice":          true,
+	"doc-pagebreak":       true,
+	"doc-pagefooter":      true,
+	"doc-pageheader":      true,
+	"doc-pagelist":        true,
+	"doc-part":            true,
+	"doc-preface":         true,
+	"doc-prologue":        true,
+	"doc-pullquote":       true,
+	"doc-qna":             true,
+	"doc-subtitle":        true,
+	"doc-tip":             true,
+	"doc-toc":             true,
+}
@@ -0,0 +1,182 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type accessibilityTestCompo struct {
+	Compo
+}
+
+func (c *accessibilityTestCompo) Render() UI {
+	return Div().Body(
+		H1().Text("Title"),
+		Img().Src("/web/logo.png"),
+		H3().Text("Subtitle"),
+	)
+}
+
+func TestTestAccessibility(t *testing.T) {
+	utests := []struct {
+		scenario string
+		ui       UI
+		expected []AccessibilityRule
+	}{
+		{
+			scenario: "accessible element",
+			ui: Div().Body(
+				H1().Text("Title"),
+				Img().Src("/logo.png").Alt("logo"),
+				Img().Src("/deco.png").Role("presentation"),
+				Img().Src("/hidden.png").Aria("hidden", true),
+				Label().For("name").Text("Name"),
+				Input().ID("name"),
+				Label().Body(
+					Text("Email"),
+					Input().Type("email"),
+				),
+				Span().ID("comment-label").Text("Comment"),
+				Textarea().Aria("labelledby", "comment-label"),
+				Select().Aria("label", "Color"),
+				Input().Type("hidden"),
+				Input().Type("submit").Value("Send"),
+				H2().Text("Section"),
+				H3().Text("Subsection"),
+				H2().Text("Section"),
+				Nav().Role("navigation"),
+			),
+		},
+		{
+			scenario: "image without alt",
+			ui:       Img().Src("/logo.png"),
+			expected: []AccessibilityRule{ImageAltRule},
+		},
+		{
+			scenario: "image input without alt",
+			ui:       Input().Type("image").Src("/send.png"),
+			expected: []AccessibilityRule{ImageAltRule},
+		},
+		{
+			scenario: "image named with aria label",
+			ui:       Img().Src("/logo.png").Aria("label", "logo"),
+		},
+		{
+			scenario: "input without label",
+			ui: Div().Body(
+				Input().Placeholder("Name"),
+				Textarea(),
+				Select(),
+			),
+			expected: []AccessibilityRule{
+				InputLabelRule,
+				InputLabelRule,
+				InputLabelRule,
+			},
+		},
+		{
+			scenario: "input labelled by a missing element",
+			ui:       Input().Aria("labelledby", "missing"),
+			expected: []AccessibilityRule{InputLabelRule},
+		},
+		{
+			scenario: "invalid aria roles",
+			ui: Div().Body(
+				Div().Role("buton"),
+				Div().Role("widget"),
+				Div().Role("switch checkbox"),
+			),
+			expected: []AccessibilityRule{
+				ARIARoleRule,
+				ARIARoleRule,
+			},
+		},
+		{
+			scenario: "heading level skip",
+			ui: Div().Body(
+				H1().Text("Title"),
+				H3().Text("Subtitle"),
+				Div().Role("heading").Aria("level", 5).Text("Subsubtitle"),
+			),
+			expected: []AccessibilityRule{
+				HeadingOrderRule,
+				HeadingOrderRule,
+			},
+		},
+		{
+			scenario: "component",
+			ui:       &accessibilityTestCompo{},
+			expected: []AccessibilityRule{
+				ImageAltRule,
+				HeadingOrderRule,
+			},
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			err := TestAccessibility(u.ui)
+			if len(u.expected) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var accessibilityErr AccessibilityError
+			require.True(t, errors.As(err, &accessibilityErr))
+
+			var rules []AccessibilityRule
+			for _, issue := range accessibilityErr.Issues {
+				rules = append(rules, issue.Rule)
+				require.NotEmpty(t, issue.Path)
+				require.NotEmpty(t, issue.Element)
+				require.NotEmpty(t, issue.Message)
+			}
+			require.Equal(t, u.expected, rules)
+		})
+	}
+}
+
+func TestTestAccessibilityWithTestEngine(t *testing.T) {
+	compo := &accessibilityTestCompo{}
+
+	e := NewTestEngine()
+	require.NoError(t, e.Load(compo))
+	e.ConsumeAll()
+
+	err := TestAccessibility(compo)
+	require.Error(t, err)
+
+	var accessibilityErr AccessibilityError
+	require.True(t, errors.As(err, &accessibilityErr))
+	require.True(t, accessibilityErr.Has(ImageAltRule))
+	require.True(t, accessibilityErr.Has(HeadingOrderRule))
+	require.False(t, accessibilityErr.Has(DocumentLangRule))
+	require.Equal(t, "div > img:nth-child(2)", accessibilityErr.Issues[0].Path)
+	require.Equal(t, `<img src="/web/logo.png">`, accessibilityErr.Issues[0].Element)
+}
+
+func TestTestAccessibilityHTML(t *testing.T) {
+	t.Run("document", func(t *testing.T) {
+		err := TestAccessibilityHTML(`<!DOCTYPE html><html><body><img src="/a.png"></body></html>`)
+		require.Error(t, err)
+
+		var accessibilityErr AccessibilityError
+		require.True(t, errors.As(err, &accessibilityErr))
+		require.Len(t, accessibilityErr.Issues, 2)
+		require.Equal(t, DocumentLangRule, accessibilityErr.Issues[0].Rule)
+		require.Equal(t, "html", accessibilityErr.Issues[0].Path)
+		require.Equal(t, ImageAltRule, accessibilityErr.Issues[1].Rule)
+		require.Equal(t, "html > body:nth-child(2) > img", accessibilityErr.Issues[1].Path)
+		require.Contains(t, err.Error(), "2 accessibility issue(s) found")
+	})
+
+	t.Run("document with lang", func(t *testing.T) {
+		require.NoError(t, TestAccessibilityHTML(`<html lang="en"><body><h1>Title</h1></body></html>`))
+	})
+
+	t.Run("fragment", func(t *testing.T) {
+		require.NoError(t, TestAccessibilityHTML(`<p>hello</p><img src="/a.png" alt="a">`))
+	})
+}
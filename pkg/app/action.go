@@ -3,6 +3,23 @@ package app
 import (
 	"fmt"
 	"sync"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v11/pkg/errors"
+)
+
+var (
+	// ErrActionTimeout is the error reported when a request made with
+	// Context.Request is not replied within its timeout.
+	ErrActionTimeout = errors.New("action request timed out")
+
+	// ErrNoActionHandler is the error reported when a request made with
+	// Context.Request has no handler.
+	ErrNoActionHandler = errors.New("no action handler")
+
+	// ErrActionReplyType is the error reported when the reply of a request made
+	// with Request is not of the expected type.
+	ErrActionReplyType = errors.New("unexpected action reply type")
 )
 
 // Action represents a custom event that can be propagated across the app. It
@@ -16,6 +33,63 @@ type Action struct {
 
 	// Tags provide additional context or metadata for the action.
 	Tags Tags
+
+	request *actionRequest
+}
+
+// IsRequest reports whether the action is a request made with
+// Context.Request that expects a reply.
+func (a Action) IsRequest() bool {
+	return a.request != nil
+}
+
+// Reply answers a request made with Context.Request with the given value. Only
+// the first reply to a request is considered. It does nothing when the action
+// is not a request.
+func (a Action) Reply(v any) {
+	a.request.respond(v, nil)
+}
+
+// ReplyError answers a request made with Context.Request with the given error.
+// Only the first reply to a request is considered. It does nothing when the
+// action is not a request.
+func (a Action) ReplyError(err error) {
+	a.request.respond(nil, err)
+}
+
+// actionRequest holds the function that delivers the reply of a request.
+type actionRequest struct {
+	once  sync.Once
+	reply func(any, error)
+
+	mutex sync.Mutex
+	timer *time.Timer
+}
+
+// setTimeout responds with the given error when no reply is received before
+// the given duration.
+func (r *actionRequest) setTimeout(d time.Duration, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.timer = time.AfterFunc(d, func() {
+		r.respond(nil, err)
+	})
+}
+
+func (r *actionRequest) respond(v any, err error) {
+	if r == nil {
+		return
+	}
+	r.once.Do(func() {
+		r.mutex.Lock()
+		if r.timer != nil {
+			r.timer.Stop()
+		}
+		r.mutex.Unlock()
+
+		r.reply(v, err)
+	})
 }
 
 // ActionHandler defines a callback executed when an action is triggered
@@ -30,6 +104,29 @@ func Handle(actionName string, h ActionHandler) {
 
 var actionHandlers = make(map[string]ActionHandler)
 
+// ActionMiddleware intercepts posted actions before they reach their handlers.
+// It can inspect or transform the action, then call next to continue posting
+// it, or drop the action by not calling next. next can be called from another
+// goroutine, which allows actions to be delayed.
+type ActionMiddleware func(ctx Context, a Action, next func(Context, Action))
+
+// UseActionMiddleware registers middlewares that intercept every action posted
+// with Context.NewAction and Context.NewActionWithValue. Middlewares are called
+// in the order they are registered. It must be called before the app is
+// started, alongside Handle calls.
+//
+// Example:
+//
+//	app.UseActionMiddleware(func(ctx app.Context, a app.Action, next func(app.Context, app.Action)) {
+//		app.Log("action posted:", a.Name)
+//		next(ctx, a)
+//	})
+func UseActionMiddleware(m ...ActionMiddleware) {
+	actionMiddlewares = append(actionMiddlewares, m...)
+}
+
+var actionMiddlewares []ActionMiddleware
+
 type actionHandler struct {
 	Source   UI
 	Function ActionHandler
@@ -39,8 +136,10 @@ type actionHandler struct {
 // actionManager manages the registration and execution of action handlers. It
 // ensures that only actions related to mounted sources are processed.
 type actionManager struct {
-	mutex    sync.Mutex
-	handlers map[string]map[string]actionHandler
+	mutex       sync.Mutex
+	handlers    map[string]map[string]actionHandler
+	middlewares []ActionMiddleware
+	debug       *debugRecorder
 }
 
 // Handle registers an ActionHandler for the given action and source.
@@ -66,11 +165,35 @@ func (m *actionManager) Handle(action string, source UI, async bool, handler Act
 	}
 }
 
-// Post processes the provided action by executing its associated handlers.
+// Post processes the provided action by passing it through the middlewares,
+// then executing its associated handlers.
 func (m *actionManager) Post(ctx Context, a Action) {
+	post := m.post
+	for i := len(m.middlewares) - 1; i >= 0; i-- {
+		middleware := m.middlewares[i]
+		next := post
+		post = func(ctx Context, a Action) {
+			middleware(ctx, a, next)
+		}
+	}
+	post(ctx, a)
+}
+
+func (m *actionManager) post(ctx Context, a Action) {
+	m.debug.Record(ctx, DebugActionPost, a.Name, a.Value, a.Tags)
+
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	handled := false
+	defer func() {
+		if a.IsRequest() && !handled {
+			a.ReplyError(errors.New("requesting action failed").
+				WithTag("action", a.Name).
+				Wrap(ErrNoActionHandler))
+		}
+	}()
+
 	for key, handler := range m.handlers[a.Name] {
 		source := handler.Source
 		if !source.Mounted() {
@@ -78,6 +201,7 @@ func (m *actionManager) Post(ctx Context, a Action) {
 			continue
 		}
 		ctx.sourceElement = source
+		handled = true
 
 		function := handler.Function
 		if handler.Async {
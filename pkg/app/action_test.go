@@ -3,6 +3,7 @@ package app
 import (
 	"testing"
 
+	"github.com/maxence-charriere/go-app/v11/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -11,6 +12,16 @@ func TestHandle(t *testing.T) {
 	require.Len(t, actionHandlers, 1)
 }
 
+func TestUseActionMiddleware(t *testing.T) {
+	defer func() { actionMiddlewares = nil }()
+
+	UseActionMiddleware(func(ctx Context, a Action, next func(Context, Action)) {
+		next(ctx, a)
+	})
+	require.Len(t, actionMiddlewares, 1)
+	require.Len(t, newTestEngine().actions.middlewares, 1)
+}
+
 func TestActionManagerHandle(t *testing.T) {
 	var m actionManager
 
@@ -101,3 +112,134 @@ func TestActionManagerCleanup(t *testing.T) {
 	m.Cleanup()
 	require.Empty(t, m.handlers)
 }
+
+func TestActionManagerPostMiddlewares(t *testing.T) {
+	t.Run("middlewares are called in order", func(t *testing.T) {
+		var nm nodeManager
+		ctx := makeTestContext()
+		source, err := nm.Mount(ctx, 1, Div())
+		require.NoError(t, err)
+		ctx = nm.context(ctx, source)
+
+		var calls []string
+		am := actionManager{
+			middlewares: []ActionMiddleware{
+				func(ctx Context, a Action, next func(Context, Action)) {
+					calls = append(calls, "first")
+					next(ctx, a)
+				},
+				func(ctx Context, a Action, next func(Context, Action)) {
+					calls = append(calls, "second")
+					require.Equal(t, source, ctx.Src())
+					require.Equal(t, "bar", a.Tags.Get("foo"))
+					a.Value = 42
+					next(ctx, a)
+				},
+			},
+		}
+
+		var value any
+		am.Handle("test", source, false, func(ctx Context, a Action) {
+			calls = append(calls, "handler")
+			value = a.Value
+		})
+
+		am.Post(ctx, Action{
+			Name: "test",
+			Tags: Tags{"foo": "bar"},
+		})
+		require.Equal(t, []string{"first", "second", "handler"}, calls)
+		require.Equal(t, 42, value)
+	})
+
+	t.Run("middleware drops action", func(t *testing.T) {
+		var nm nodeManager
+		ctx := makeTestContext()
+		source, err := nm.Mount(ctx, 1, Div())
+		require.NoError(t, err)
+		ctx = nm.context(ctx, source)
+
+		am := actionManager{
+			middlewares: []ActionMiddleware{
+				func(ctx Context, a Action, next func(Context, Action)) {},
+			},
+		}
+
+		handlerCalled := false
+		am.Handle("test", source, false, func(ctx Context, a Action) {
+			handlerCalled = true
+		})
+
+		am.Post(ctx, Action{Name: "test"})
+		require.False(t, handlerCalled)
+	})
+
+	t.Run("dropped action is not recorded", func(t *testing.T) {
+		var nm nodeManager
+		ctx := makeTestContext()
+		source, err := nm.Mount(ctx, 1, Div())
+		require.NoError(t, err)
+		ctx = nm.context(ctx, source)
+
+		am := actionManager{
+			debug: newDebugRecorder(10),
+			middlewares: []ActionMiddleware{
+				func(ctx Context, a Action, next func(Context, Action)) {
+					if a.Name == "dropped" {
+						return
+					}
+					next(ctx, a)
+				},
+			},
+		}
+
+		am.Post(ctx, Action{Name: "dropped"})
+		am.Post(ctx, Action{Name: "posted"})
+
+		events := am.debug.Events()
+		require.Len(t, events, 1)
+		require.Equal(t, "posted", events[0].Name)
+	})
+}
+
+func TestActionReply(t *testing.T) {
+	t.Run("reply to non request action is ignored", func(t *testing.T) {
+		a := Action{Name: "test"}
+		require.False(t, a.IsRequest())
+		a.Reply(42)
+		a.ReplyError(errors.New("test"))
+	})
+
+	t.Run("only the first reply is considered", func(t *testing.T) {
+		var replies []any
+		a := Action{
+			Name: "test",
+			request: &actionRequest{
+				reply: func(v any, err error) {
+					replies = append(replies, v)
+				},
+			},
+		}
+		require.True(t, a.IsRequest())
+
+		a.Reply(21)
+		a.Reply(42)
+		a.ReplyError(errors.New("test"))
+		require.Equal(t, []any{21}, replies)
+	})
+
+	t.Run("request without handler replies an error", func(t *testing.T) {
+		var am actionManager
+		var err error
+		am.Post(makeTestContext(), Action{
+			Name: "test",
+			request: &actionRequest{
+				reply: func(v any, e error) {
+					err = e
+				},
+			},
+		})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrNoActionHandler))
+	})
+}
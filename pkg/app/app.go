@@ -93,6 +93,12 @@ func RunWhenOnBrowser() {
 
 	defer func() {
 		err := recover()
+		if err != nil {
+			reportError(ErrorReport{
+				Err:   panicError(err),
+				Panic: true,
+			})
+		}
 		displayLoadError(err)
 		panic(err)
 	}()
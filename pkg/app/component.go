@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"reflect"
 	"strings"
 
@@ -37,6 +38,9 @@ type Composer interface {
 	parent() UI
 	root() UI
 	setRoot(UI) Composer
+	lifecycle() context.Context
+	setLifecycle(context.Context, context.CancelFunc) Composer
+	endLifecycle()
 }
 
 // Initializer describes a component that requires initialization
@@ -81,6 +85,26 @@ type Dismounter interface {
 	OnDismount()
 }
 
+// ErrorBoundary describes components that catch the failures of their
+// subtree. A failure is an error or a panic that occurs while rendering,
+// mounting or updating the descendants of the component, including in their
+// OnInit and OnUpdate hooks, or a panic that occurs in a function they
+// dispatched, such as OnMount, OnNav or an event handler. Panics that occur in
+// OnDismount or in goroutines started by the descendants are not caught.
+//
+// When a failure is caught, OnError is called and the component is rendered
+// again, allowing it to display a fallback UI while the rest of the app keeps
+// running. A component does not catch its own failures, which are passed to the
+// closest error boundary among its ancestors.
+type ErrorBoundary interface {
+	Composer
+
+	// OnError is called when a failure occurs in the subtree of the component.
+	// It should record the error in order to render a fallback UI.
+	// This function is executed within the UI goroutine.
+	OnError(ctx Context, err error)
+}
+
 // Navigator characterizes components that need to perform specific
 // actions or initializations when they become the target of navigation.
 // By adopting the Navigator interface, components can specify behaviors
@@ -161,10 +185,12 @@ type Resizer interface {
 // Compo serves as the foundational struct for constructing a component. It
 // provides basic methods and fields needed for component management.
 type Compo struct {
-	treeDepth     uint
-	ref           Composer
-	parentElement UI
-	rootElement   UI
+	treeDepth       uint
+	ref             Composer
+	parentElement   UI
+	rootElement     UI
+	lifecycleCtx    context.Context
+	cancelLifecycle context.CancelFunc
 }
 
 // JSValue retrieves the JavaScript value associated with the component's root.
@@ -249,3 +275,21 @@ func (c *Compo) setRoot(v UI) Composer {
 	c.rootElement = v
 	return c.ref
 }
+
+func (c *Compo) lifecycle() context.Context {
+	return c.lifecycleCtx
+}
+
+func (c *Compo) setLifecycle(ctx context.Context, cancel context.CancelFunc) Composer {
+	c.lifecycleCtx = ctx
+	c.cancelLifecycle = cancel
+	return c.ref
+}
+
+func (c *Compo) endLifecycle() {
+	if c.cancelLifecycle != nil {
+		c.cancelLifecycle()
+	}
+	c.lifecycleCtx = nil
+	c.cancelLifecycle = nil
+}
@@ -159,3 +159,48 @@ type dismountEnforcerComponent struct {
 func (c *dismountEnforcerComponent) CompoID() string {
 	return c.id
 }
+
+type errorBoundaryComponent struct {
+	Compo
+
+	Child UI
+	err   error
+}
+
+func (c *errorBoundaryComponent) OnError(ctx Context, err error) {
+	c.err = err
+}
+
+func (c *errorBoundaryComponent) Render() UI {
+	if c.err != nil {
+		return P().Text("fallback")
+	}
+	return Div().Body(c.Child)
+}
+
+type panicComponent struct {
+	Compo
+
+	Panic bool
+}
+
+func (c *panicComponent) Render() UI {
+	if c.Panic {
+		panic("render panic")
+	}
+	return Text("ok")
+}
+
+type updatePanicComponent struct {
+	Compo
+
+	Value int
+}
+
+func (c *updatePanicComponent) OnUpdate(ctx Context) {
+	panic("update panic")
+}
+
+func (c *updatePanicComponent) Render() UI {
+	return Text(c.Value)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/url"
+	"reflect"
 	"strings"
 	"time"
 
@@ -13,6 +14,9 @@ import (
 
 // Context represents a UI element-associated environment enabling interactions
 // with the browser, page navigation, concurrency, and component communication.
+//
+// The embedded context.Context is scoped to the enclosing component and is
+// cancelled when the component is dismounted.
 type Context struct {
 	context.Context
 
@@ -22,9 +26,14 @@ type Context struct {
 	navigate              func(*url.URL, bool)
 	localStorage          BrowserStorage
 	sessionStorage        BrowserStorage
+	indexedDB             IndexedDBStorage
+	cookies               BrowserStorage
 	dispatch              func(func())
 	defere                func(func())
 	async                 func(func())
+	afterFunc             func(Context, time.Duration, func(Context)) Timer
+	debounce              func(Context, string, time.Duration, func(Context))
+	throttle              func(Context, string, time.Duration, func(Context))
 	addComponentUpdate    func(Composer, int)
 	removeComponentUpdate func(Composer)
 	handleAction          func(string, UI, bool, ActionHandler)
@@ -33,6 +42,15 @@ type Context struct {
 	getState              func(Context, string, any)
 	setState              func(Context, string, any) State
 	delState              func(Context, string)
+	deriveState           func(Context, string, func(Context) any, ...string)
+	stateTransaction      func(Context, func(Context))
+	undo                  func(Context)
+	redo                  func(Context)
+	canUndo               func() bool
+	canRedo               func() bool
+	matchMedia            func(Context, string) bool
+	debug                 DebugInspector
+	catchPanic            func(Context, any) bool
 
 	sourceElement        UI
 	notifyComponentEvent func(Context, UI, any)
@@ -148,6 +166,23 @@ func (ctx Context) SessionStorage() BrowserStorage {
 	return ctx.sessionStorage
 }
 
+// IndexedDB accesses the browser's IndexedDB storage tied to the document
+// origin. It is suited for data that exceeds the local storage capacity.
+func (ctx Context) IndexedDB() IndexedDBStorage {
+	return ctx.indexedDB
+}
+
+// Cookies accesses the cookies of the document. On the server, during
+// pre-rendering, the cookies are read from the page request and changes are
+// sent with the response.
+//
+// Keys are stored in cookies whose name is prefixed with "goapp_": other
+// cookies, such as third-party ones, are neither listed nor cleared. Setting a
+// value returns an error when the cookie exceeds 4KB.
+func (ctx Context) Cookies() BrowserStorage {
+	return ctx.cookies
+}
+
 // Encrypt enciphers a value using AES encryption.
 func (ctx Context) Encrypt(v any) ([]byte, error) {
 	b, err := json.Marshal(v)
@@ -197,6 +232,7 @@ func (ctx Context) Dispatch(v func(Context)) {
 		}
 
 		if v != nil {
+			defer ctx.recoverPanic()
 			v(ctx)
 		}
 	})
@@ -211,13 +247,29 @@ func (ctx Context) Defer(v func(Context)) {
 		}
 
 		if v != nil {
+			defer ctx.recoverPanic()
 			v(ctx)
 		}
 	})
 }
 
+// recoverPanic passes a panic that occurred in a dispatched function to the
+// closest error boundary. The panic continues when there is none.
+func (ctx Context) recoverPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if ctx.catchPanic == nil || !ctx.catchPanic(ctx, r) {
+		panic(r)
+	}
+}
+
 // Async initiates a function asynchronously. It enables go-app to monitor
 // goroutines, ensuring they conclude when rendering server-side.
+//
+// The context of a component is cancelled when the component is dismounted.
+// Long-running functions should stop when ctx.Done() is closed.
 func (ctx Context) Async(v func()) {
 	ctx.async(v)
 }
@@ -230,6 +282,30 @@ func (ctx Context) After(d time.Duration, f func(Context)) {
 	})
 }
 
+// AfterFunc schedules a function to be executed on the UI goroutine after a
+// determined span. The returned Timer can be used to cancel the execution,
+// which is also cancelled when the source element is dismounted.
+func (ctx Context) AfterFunc(d time.Duration, f func(Context)) Timer {
+	return ctx.afterFunc(ctx, d, f)
+}
+
+// Debounce executes a function on the UI goroutine once a determined span
+// elapsed without another call with the same key from the source element. It
+// is useful to react to a burst of events only once, such as keystrokes in a
+// search field. Pending executions are cancelled when the source element is
+// dismounted.
+func (ctx Context) Debounce(key string, d time.Duration, f func(Context)) {
+	ctx.debounce(ctx, key, d, f)
+}
+
+// Throttle executes a function on the UI goroutine at most once per determined
+// span for a given key and source element. The first call is executed
+// immediately and the last call made during the span is executed when it ends.
+// Pending executions are cancelled when the source element is dismounted.
+func (ctx Context) Throttle(key string, d time.Duration, f func(Context)) {
+	ctx.throttle(ctx, key, d, f)
+}
+
 // PreventUpdate halts updates for the enclosing component.
 func (ctx Context) PreventUpdate() {
 	for c, ok := component(ctx.sourceElement); ok; c, ok = component(c.parent()) {
@@ -256,6 +332,77 @@ func (ctx Context) NewAction(action string, tags ...Tagger) {
 
 // NewActionWithValue crafts an action with a given value for processing.
 func (ctx Context) NewActionWithValue(action string, v any, tags ...Tagger) {
+	ctx.postAction(ctx, Action{
+		Name:  action,
+		Value: v,
+		Tags:  mergeTags(tags),
+	})
+}
+
+// Request crafts an action with a given value and calls h on the UI goroutine
+// with the reply of the first handler that answers it with Action.Reply or
+// Action.ReplyError. Handlers set with Handle and Context.Handle can reply.
+//
+// h is called with an error that wraps ErrNoActionHandler when the action has
+// no handler, or ErrActionTimeout when no reply is received within the given
+// timeout. The timeout must be greater than 0, which also ensures h is called
+// when the action is dropped by a middleware.
+func (ctx Context) Request(action string, v any, timeout time.Duration, h func(Context, any, error), tags ...Tagger) {
+	if timeout <= 0 {
+		ctx.Dispatch(func(ctx Context) {
+			h(ctx, nil, errors.New("invalid action request timeout").
+				WithTag("action", action).
+				WithTag("timeout", timeout))
+		})
+		return
+	}
+
+	request := &actionRequest{
+		reply: func(v any, err error) {
+			ctx.Dispatch(func(ctx Context) {
+				h(ctx, v, err)
+			})
+		},
+	}
+	request.setTimeout(timeout, errors.New("requesting action failed").
+		WithTag("action", action).
+		WithTag("timeout", timeout).
+		Wrap(ErrActionTimeout))
+
+	ctx.postAction(ctx, Action{
+		Name:    action,
+		Value:   v,
+		Tags:    mergeTags(tags),
+		request: request,
+	})
+}
+
+// Request is the typed version of Context.Request. h is called with an error
+// that wraps ErrActionReplyType when the reply is not of type T.
+func Request[T any](ctx Context, action string, v any, timeout time.Duration, h func(Context, T, error), tags ...Tagger) {
+	ctx.Request(action, v, timeout, func(ctx Context, reply any, err error) {
+		var value T
+		if err != nil {
+			h(ctx, value, err)
+			return
+		}
+
+		if reply != nil {
+			var ok bool
+			if value, ok = reply.(T); !ok {
+				h(ctx, value, errors.New("requesting action failed").
+					WithTag("action", action).
+					WithTag("reply-type", reflect.TypeOf(reply)).
+					WithTag("expected-type", reflect.TypeOf(&value).Elem()).
+					Wrap(ErrActionReplyType))
+				return
+			}
+		}
+		h(ctx, value, nil)
+	}, tags...)
+}
+
+func mergeTags(tags []Tagger) Tags {
 	var tagMap Tags
 	for _, tag := range tags {
 		if tagMap == nil {
@@ -266,12 +413,7 @@ func (ctx Context) NewActionWithValue(action string, v any, tags ...Tagger) {
 			tagMap[k] = v
 		}
 	}
-
-	ctx.postAction(ctx, Action{
-		Name:  action,
-		Value: v,
-		Tags:  tagMap,
-	})
+	return tagMap
 }
 
 // ObserveState establishes an observer for a state, tracking its changes.
@@ -294,6 +436,77 @@ func (ctx Context) DelState(state string) {
 	ctx.delState(ctx, state)
 }
 
+// DeriveState declares a state whose value is computed from other states. The
+// compute function is called lazily when the derived state is read, or when
+// one of its dependencies changes while the derived state is observed.
+// Observers of the derived state are notified like with any other state.
+//
+// Example:
+//
+//	ctx.DeriveState("cart-total", func(ctx app.Context) any {
+//		var items []Item
+//		ctx.GetState("cart-items", &items)
+//
+//		var total float64
+//		for _, item := range items {
+//			total += item.Price
+//		}
+//		return total
+//	}, "cart-items")
+func (ctx Context) DeriveState(state string, compute func(Context) any, dependencies ...string) {
+	ctx.deriveState(ctx, state, compute, dependencies...)
+}
+
+// StateTransaction executes the given function and groups the changes it
+// makes to the states tracked with StateConfig.TrackHistory into a single undo
+// step.
+func (ctx Context) StateTransaction(f func(Context)) {
+	ctx.stateTransaction(ctx, f)
+}
+
+// Undo reverts the last changes made to the states tracked with
+// StateConfig.TrackHistory. Observers are notified as if the states were set.
+func (ctx Context) Undo() {
+	ctx.undo(ctx)
+}
+
+// Redo reapplies the last changes reverted with Undo. Redo is no longer
+// possible once a tracked state is modified.
+func (ctx Context) Redo() {
+	ctx.redo(ctx)
+}
+
+// CanUndo reports whether there are state changes that can be reverted with
+// Undo.
+func (ctx Context) CanUndo() bool {
+	return ctx.canUndo()
+}
+
+// CanRedo reports whether there are state changes that can be reapplied with
+// Redo.
+func (ctx Context) CanRedo() bool {
+	return ctx.canRedo()
+}
+
+// RequestLeadership elects a single browser tab or window among the ones
+// sharing the same origin to run the given function. It is useful to ensure
+// that only one tab performs tasks such as polling or maintaining a socket.
+//
+// The function is called once the current tab becomes the leader for the given
+// name, with a context that is cancelled when the leadership is lost.
+// Leadership is kept until the context is done or the tab is closed, at which
+// point another tab requesting it takes over. It does nothing when rendering
+// server-side.
+func (ctx Context) RequestLeadership(name string, f func(Context)) {
+	requestLeadership(ctx, name, f)
+}
+
+// Debug provides access to the states and the events recorded when the debug
+// recorder is enabled with EnableDebugRecorder.
+func (ctx Context) Debug() DebugInspector {
+	return ctx.debug
+}
+
 // ResizeContent notifies the children of the associated element that implement
 // the Resizer interface about a resize event. It ensures that components can
 // adjust their size and layout in response to changes. This method is typically
@@ -306,3 +519,23 @@ func (ctx Context) ResizeContent() {
 		})
 	})
 }
+
+// MatchMedia reports whether the page matches the given CSS media query, such
+// as "(min-width: 768px)" or "(prefers-color-scheme: dark)". The enclosing
+// component is updated when the result of the query changes, without waiting
+// for a resize event.
+//
+// During pre-rendering, only width and height queries are evaluated, against
+// the viewport size hinted by the request.
+func (ctx Context) MatchMedia(query string) bool {
+	return ctx.matchMedia(ctx, query)
+}
+
+// Breakpoint returns the name of the breakpoint that matches the page width.
+// The enclosing component is updated only when the matching breakpoint
+// changes. See SetBreakpoints.
+func (ctx Context) Breakpoint() string {
+	return breakpoint(func(query string) bool {
+		return ctx.MatchMedia(query)
+	})
+}
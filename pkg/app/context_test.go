@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/maxence-charriere/go-app/v11/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -111,6 +112,14 @@ func TestContextStorage(t *testing.T) {
 	t.Run("session storage is set", func(t *testing.T) {
 		require.NotZero(t, ctx.SessionStorage())
 	})
+
+	t.Run("indexeddb storage is set", func(t *testing.T) {
+		require.NotZero(t, ctx.IndexedDB())
+	})
+
+	t.Run("cookies storage is set", func(t *testing.T) {
+		require.NotZero(t, ctx.Cookies())
+	})
 }
 
 func TestContextEncryptDecryptStruct(t *testing.T) {
@@ -318,6 +327,99 @@ func TestContextHandle(t *testing.T) {
 	require.Nil(t, action.Tags)
 }
 
+func TestContextRequest(t *testing.T) {
+	e := newTestEngine()
+
+	hello := &hello{}
+	e.Load(hello)
+	ctx := e.nodes.context(e.baseContext(), hello)
+
+	t.Run("request is replied", func(t *testing.T) {
+		actionName := "/test/context/request"
+		ctx.Handle(actionName, func(ctx Context, a Action) {
+			a.Reply(a.Value.(int) * 2)
+		})
+
+		var reply any
+		var err error
+		ctx.Request(actionName, 21, time.Minute, func(ctx Context, v any, e error) {
+			reply = v
+			err = e
+		}, T("hello", "world"))
+		e.ConsumeAll()
+		require.NoError(t, err)
+		require.Equal(t, 42, reply)
+	})
+
+	t.Run("request without handler fails", func(t *testing.T) {
+		var err error
+		ctx.Request("/test/context/request/nohandler", nil, time.Minute, func(ctx Context, v any, e error) {
+			err = e
+		})
+		e.ConsumeAll()
+		require.True(t, errors.Is(err, ErrNoActionHandler))
+	})
+
+	t.Run("request without timeout fails", func(t *testing.T) {
+		var err error
+		ctx.Request("/test/context/request", nil, 0, func(ctx Context, v any, e error) {
+			err = e
+		})
+		e.ConsumeAll()
+		require.Error(t, err)
+	})
+
+	t.Run("reply stops the timeout", func(t *testing.T) {
+		request := &actionRequest{
+			reply: func(any, error) {},
+		}
+		request.setTimeout(time.Minute, errors.New("timeout"))
+		request.respond(nil, nil)
+		require.False(t, request.timer.Stop())
+	})
+
+	t.Run("typed request is replied", func(t *testing.T) {
+		var reply int
+		var err error
+		Request(ctx, "/test/context/request", 21, time.Minute, func(ctx Context, v int, e error) {
+			reply = v
+			err = e
+		})
+		e.ConsumeAll()
+		require.NoError(t, err)
+		require.Equal(t, 42, reply)
+	})
+
+	t.Run("typed request with unexpected reply type fails", func(t *testing.T) {
+		var err error
+		Request(ctx, "/test/context/request", 21, time.Minute, func(ctx Context, v string, e error) {
+			err = e
+		})
+		e.ConsumeAll()
+		require.True(t, errors.Is(err, ErrActionReplyType))
+	})
+
+	t.Run("request times out", func(t *testing.T) {
+		actionName := "/test/context/request/timeout"
+		ctx.Handle(actionName, func(ctx Context, a Action) {})
+
+		done := make(chan error, 1)
+		ctx.Request(actionName, nil, time.Millisecond, func(ctx Context, v any, err error) {
+			done <- err
+		})
+
+		var err error
+		for err == nil {
+			e.ConsumeAll()
+			select {
+			case err = <-done:
+			case <-time.After(time.Millisecond * 5):
+			}
+		}
+		require.True(t, errors.Is(err, ErrActionTimeout))
+	})
+}
+
 func TestContextStates(t *testing.T) {
 	e := newTestEngine()
 
@@ -342,6 +444,31 @@ func TestContextStates(t *testing.T) {
 	require.Empty(t, e.states.states)
 }
 
+func TestContextDeriveState(t *testing.T) {
+	e := newTestEngine()
+
+	hello := &hello{}
+	e.Load(hello)
+	ctx := e.nodes.context(e.baseContext(), hello)
+
+	state := "/test/context/derived-states"
+	derived := state + "/double"
+	ctx.SetState(state, 21)
+	ctx.DeriveState(derived, func(ctx Context) any {
+		var v int
+		ctx.GetState(state, &v)
+		return v * 2
+	}, state)
+
+	var v int
+	ctx.ObserveState(derived, &v)
+	require.Equal(t, 42, v)
+
+	ctx.SetState(state, 50)
+	e.ConsumeAll()
+	require.Equal(t, 100, v)
+}
+
 func TestContextResizeContent(t *testing.T) {
 	e := newTestEngine()
 	hello := &hello{}
@@ -367,12 +494,15 @@ func makeTestContext() Context {
 
 	var localStorage BrowserStorage
 	var sessionStorage BrowserStorage
+	var cookies BrowserStorage
 	if IsServer {
 		localStorage = newMemoryStorage()
 		sessionStorage = newMemoryStorage()
+		cookies = newHTTPCookieStorage(nil)
 	} else {
 		localStorage = newJSStorage("localStorage")
 		sessionStorage = newJSStorage("sessionStorage")
+		cookies = newJSCookieStorage()
 	}
 
 	return Context{
@@ -381,6 +511,8 @@ func makeTestContext() Context {
 		resolveURL:            resolveURL,
 		localStorage:          localStorage,
 		sessionStorage:        sessionStorage,
+		indexedDB:             newIndexedDBStorage("goapp-test"),
+		cookies:               cookies,
 		dispatch:              func(f func()) { f() },
 		defere:                func(f func()) { f() },
 		async:                 func(f func()) { f() },
@@ -388,5 +520,6 @@ func makeTestContext() Context {
 		removeComponentUpdate: func(Composer) {},
 		handleAction:          func(string, UI, bool, ActionHandler) {},
 		postAction:            func(Context, Action) {},
+		matchMedia:            func(Context, string) bool { return false },
 	}
 }
@@ -0,0 +1,415 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v11/pkg/errors"
+)
+
+const (
+	defaultDebugCapacity        = 1000
+	debugOverlayRefreshInterval = time.Millisecond * 500
+)
+
+var (
+	debugCapacity int
+)
+
+// EnableDebugRecorder turns on the recording of state mutations and action
+// posts. Recorded events can be inspected with Context.Debug() or displayed
+// with the DebugOverlay component. At most capacity events are kept, older
+// ones being discarded first. A capacity less than or equal to 0 uses a
+// default capacity.
+//
+// Recording keeps references to state and action values in memory: values are
+// not copied and should not be modified after being set or posted, otherwise
+// the recorded events and the states restored with DebugInspector.Restore
+// reflect the modifications. Actions dropped by a middleware are not recorded.
+// It is intended for development and must be called before the app is started.
+func EnableDebugRecorder(capacity int) {
+	if capacity <= 0 {
+		capacity = defaultDebugCapacity
+	}
+	debugCapacity = capacity
+}
+
+// DebugEventType represents the type of a recorded debug event.
+type DebugEventType string
+
+const (
+	// DebugStateSet is the type of events recorded when a state is set.
+	DebugStateSet DebugEventType = "state-set"
+
+	// DebugStateDelete is the type of events recorded when a state is
+	// deleted.
+	DebugStateDelete DebugEventType = "state-delete"
+
+	// DebugActionPost is the type of events recorded when an action is
+	// posted and passed through the middlewares.
+	DebugActionPost DebugEventType = "action-post"
+)
+
+// DebugEvent represents a recorded state mutation or action post.
+type DebugEvent struct {
+	// ID is the sequence number of the event.
+	ID int
+
+	// Time is when the event occurred.
+	Time time.Time
+
+	// Type is the type of the event.
+	Type DebugEventType
+
+	// Name is the name of the state or action.
+	Name string
+
+	// Value is the value of the state or action. It is nil when a state is
+	// deleted.
+	Value any
+
+	// Tags are the tags of a posted action.
+	Tags Tags
+
+	// Source is the type of the component that produced the event. It is
+	// empty when the event did not originate from a component.
+	Source string
+}
+
+// DebugState describes a state currently held in memory.
+type DebugState struct {
+	// Name is the name of the state.
+	Name string
+
+	// Value is the value of the state.
+	Value any
+
+	// Derived reports whether the state is computed from other states.
+	Derived bool
+
+	// Observers are the types of the components observing the state.
+	Observers []string
+}
+
+// DebugInspector provides access to the states and the events recorded when
+// the debug recorder is enabled with EnableDebugRecorder.
+type DebugInspector struct {
+	recorder *debugRecorder
+	states   *stateManager
+}
+
+// Enabled reports whether the debug recorder is enabled.
+func (d DebugInspector) Enabled() bool {
+	return d.recorder != nil
+}
+
+// Events returns the recorded events, from the oldest to the newest.
+func (d DebugInspector) Events() []DebugEvent {
+	if d.recorder == nil {
+		return nil
+	}
+	return d.recorder.Events()
+}
+
+// States returns the states currently held in memory, sorted by name, with
+// the components that observe them.
+func (d DebugInspector) States() []DebugState {
+	if d.states == nil {
+		return nil
+	}
+	return d.states.debugStates()
+}
+
+// Restore sets the states back to the values they had right after the event
+// with the given ID. Observers are notified as if the states were set.
+//
+// Restoring does not record new events and only affects the states mutated
+// by the recorded events.
+func (d DebugInspector) Restore(ctx Context, eventID int) error {
+	if d.recorder == nil || d.states == nil {
+		return errors.New("debug recorder not enabled")
+	}
+
+	snapshot, err := d.recorder.snapshot(eventID)
+	if err != nil {
+		return err
+	}
+	d.states.restore(ctx, snapshot)
+	return nil
+}
+
+type debugRecorder struct {
+	mutex    sync.Mutex
+	capacity int
+	nextID   int
+	events   []DebugEvent
+	baseline map[string]stateSnapshot
+}
+
+func newDebugRecorder(capacity int) *debugRecorder {
+	if capacity <= 0 {
+		return nil
+	}
+
+	return &debugRecorder{
+		capacity: capacity,
+		nextID:   1,
+		baseline: make(map[string]stateSnapshot),
+	}
+}
+
+func (r *debugRecorder) Record(ctx Context, t DebugEventType, name string, v any, tags Tags) {
+	if r == nil {
+		return
+	}
+
+	var source string
+	if c, ok := component(ctx.sourceElement); ok {
+		source = fmt.Sprintf("%T", c)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(r.events) == r.capacity {
+		r.evict()
+	}
+	r.events = append(r.events, DebugEvent{
+		ID:     r.nextID,
+		Time:   time.Now(),
+		Type:   t,
+		Name:   name,
+		Value:  v,
+		Tags:   tags,
+		Source: source,
+	})
+	r.nextID++
+}
+
+// evict discards the oldest event and keeps track of the state value it
+// produced in order to restore snapshots that predate the remaining events.
+func (r *debugRecorder) evict() {
+	event := r.events[0]
+	switch event.Type {
+	case DebugStateSet:
+		r.baseline[event.Name] = stateSnapshot{value: event.Value, set: true}
+
+	case DebugStateDelete:
+		r.baseline[event.Name] = stateSnapshot{}
+	}
+
+	copy(r.events, r.events[1:])
+	r.events = r.events[:len(r.events)-1]
+}
+
+// lastEventID returns the ID of the last recorded event, or 0 when no event
+// has been recorded.
+func (r *debugRecorder) lastEventID() int {
+	if r == nil {
+		return 0
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.nextID - 1
+}
+
+func (r *debugRecorder) Events() []DebugEvent {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]DebugEvent(nil), r.events...)
+}
+
+// snapshot returns the values of the recorded states right after the event
+// with the given ID.
+func (r *debugRecorder) snapshot(eventID int) (map[string]stateSnapshot, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(r.events) == 0 ||
+		eventID < r.events[0].ID ||
+		eventID > r.events[len(r.events)-1].ID {
+		return nil, errors.New("debug event not found").WithTag("id", eventID)
+	}
+
+	snapshot := make(map[string]stateSnapshot, len(r.baseline))
+	for name, v := range r.baseline {
+		snapshot[name] = v
+	}
+
+	for _, event := range r.events {
+		var v stateSnapshot
+		switch event.Type {
+		case DebugStateSet:
+			v = stateSnapshot{value: event.Value, set: true}
+
+		case DebugStateDelete:
+
+		default:
+			continue
+		}
+
+		if event.ID > eventID {
+			if _, ok := snapshot[event.Name]; !ok {
+				snapshot[event.Name] = stateSnapshot{}
+			}
+			continue
+		}
+		snapshot[event.Name] = v
+	}
+	return snapshot, nil
+}
+
+// DebugOverlay returns a devtools-style panel that displays the states held in
+// memory and the events recorded by the debug recorder. Clicking on a state
+// event restores the states as they were right after it.
+//
+// It displays nothing when the debug recorder is not enabled with
+// EnableDebugRecorder.
+func DebugOverlay() UI {
+	return &debugOverlay{}
+}
+
+type debugOverlay struct {
+	Compo
+
+	enabled     bool
+	expanded    bool
+	lastEventID int
+	events      []DebugEvent
+	states      []DebugState
+	timer       Timer
+}
+
+func (o *debugOverlay) OnMount(ctx Context) {
+	o.enabled = ctx.Debug().Enabled()
+	if o.enabled {
+		o.lastEventID = -1
+		o.refresh(ctx)
+	}
+}
+
+func (o *debugOverlay) OnDismount() {
+	o.timer.Stop()
+}
+
+// refresh copies the recorded events and the states when an event has been
+// recorded since the last refresh. The overlay is not rendered again
+// otherwise, in order to not skew the app being debugged.
+func (o *debugOverlay) refresh(ctx Context) {
+	if !o.Mounted() {
+		return
+	}
+	o.timer = ctx.AfterFunc(debugOverlayRefreshInterval, o.refresh)
+
+	debug := ctx.Debug()
+	lastEventID := debug.recorder.lastEventID()
+	if lastEventID == o.lastEventID {
+		ctx.PreventUpdate()
+		return
+	}
+
+	o.lastEventID = lastEventID
+	o.events = debug.Events()
+	o.states = debug.States()
+}
+
+func (o *debugOverlay) Render() UI {
+	if !o.enabled {
+		return Div().Hidden(true)
+	}
+
+	return Aside().
+		Style("position", "fixed").
+		Style("right", "0").
+		Style("bottom", "0").
+		Style("z-index", "10000").
+		Style("max-width", "100vw").
+		Style("max-height", "50vh").
+		Style("overflow", "auto").
+		Style("padding", "6px 12px").
+		Style("font-family", "monospace").
+		Style("font-size", "12px").
+		Style("color", "white").
+		Style("background-color", "rgba(30, 30, 30, 0.92)").
+		Body(
+			Button().
+				Style("font", "inherit").
+				Text(fmt.Sprintf("go-app debug: %v states, %v events", len(o.states), len(o.events))).
+				OnClick(o.toggle),
+			If(o.expanded, func() UI {
+				return Div().Body(
+					H4().Text("States"),
+					Ul().Body(
+						Range(o.states).Slice(func(i int) UI {
+							s := o.states[i]
+							return Li().Text(fmt.Sprintf("%s = %+v %v", s.Name, s.Value, s.Observers))
+						}),
+					),
+					H4().Text("Events"),
+					Ol().Body(
+						Range(o.events).Slice(func(i int) UI {
+							e := o.events[len(o.events)-1-i]
+							item := Li().
+								Title(e.Time.Format(time.RFC3339Nano)).
+								Text(fmt.Sprintf("#%v %s %s %+v %s", e.ID, e.Type, e.Name, e.Value, e.Source))
+							if e.Type == DebugActionPost {
+								return item
+							}
+							return item.
+								Style("cursor", "pointer").
+								OnClick(func(ctx Context, _ Event) {
+									o.restore(ctx, e.ID)
+								})
+						}),
+					),
+				)
+			}),
+		)
+}
+
+func (o *debugOverlay) toggle(ctx Context, e Event) {
+	o.expanded = !o.expanded
+}
+
+func (o *debugOverlay) restore(ctx Context, eventID int) {
+	if err := ctx.Debug().Restore(ctx, eventID); err != nil {
+		Log(errors.New("restoring debug snapshot failed").Wrap(err))
+	}
+}
+
+func (m *stateManager) debugStates() []DebugState {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	states := make([]DebugState, 0, len(m.states))
+	for name, state := range m.states {
+		observers := make([]string, 0, len(m.observers[name]))
+		for source := range m.observers[name] {
+			observers = append(observers, fmt.Sprintf("%T", source))
+		}
+		sort.Strings(observers)
+
+		_, derived := m.derivedStates[name]
+		states = append(states, DebugState{
+			Name:      name,
+			Value:     state.value,
+			Derived:   derived,
+			Observers: observers,
+		})
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Name < states[j].Name
+	})
+	return states
+}
+
+// restore sets the given state values without recording debug events.
+func (m *stateManager) restore(ctx Context, snapshot map[string]stateSnapshot) {
+	for name, v := range snapshot {
+		m.applySnapshot(ctx, name, v)
+	}
+}
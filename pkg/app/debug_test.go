@@ -0,0 +1,247 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableDebugRecorder(t *testing.T) {
+	defer func() { debugCapacity = 0 }()
+
+	EnableDebugRecorder(0)
+	require.Equal(t, defaultDebugCapacity, debugCapacity)
+
+	EnableDebugRecorder(42)
+	require.Equal(t, 42, debugCapacity)
+}
+
+func TestDebugRecorder(t *testing.T) {
+	t.Run("disabled recorder is nil", func(t *testing.T) {
+		r := newDebugRecorder(0)
+		require.Nil(t, r)
+		r.Record(makeTestContext(), DebugStateSet, "test", 42, nil)
+	})
+
+	t.Run("events are recorded", func(t *testing.T) {
+		e := newTestEngine()
+		compo := &hello{}
+		e.Load(compo)
+		ctx := e.nodes.context(e.baseContext(), compo)
+
+		r := newDebugRecorder(10)
+		r.Record(ctx, DebugActionPost, "action", 21, Tags{"foo": "bar"})
+
+		events := r.Events()
+		require.Len(t, events, 1)
+		require.Equal(t, 1, events[0].ID)
+		require.NotZero(t, events[0].Time)
+		require.Equal(t, DebugActionPost, events[0].Type)
+		require.Equal(t, "action", events[0].Name)
+		require.Equal(t, 21, events[0].Value)
+		require.Equal(t, "bar", events[0].Tags.Get("foo"))
+		require.Equal(t, "*app.hello", events[0].Source)
+	})
+
+	t.Run("oldest events are evicted", func(t *testing.T) {
+		ctx := makeTestContext()
+		r := newDebugRecorder(2)
+		r.Record(ctx, DebugStateSet, "a", 1, nil)
+		r.Record(ctx, DebugStateSet, "b", 2, nil)
+		r.Record(ctx, DebugStateSet, "a", 3, nil)
+
+		events := r.Events()
+		require.Len(t, events, 2)
+		require.Equal(t, 2, events[0].ID)
+		require.Equal(t, 3, events[1].ID)
+		require.Equal(t, stateSnapshot{value: 1, set: true}, r.baseline["a"])
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		ctx := makeTestContext()
+		r := newDebugRecorder(3)
+		r.Record(ctx, DebugStateSet, "a", 1, nil)
+		r.Record(ctx, DebugStateSet, "a", 2, nil)
+		r.Record(ctx, DebugStateSet, "b", 3, nil)
+		r.Record(ctx, DebugActionPost, "action", nil, nil)
+		r.Record(ctx, DebugStateDelete, "a", nil, nil)
+
+		snapshot, err := r.snapshot(3)
+		require.NoError(t, err)
+		require.Equal(t, map[string]stateSnapshot{
+			"a": {value: 2, set: true},
+			"b": {value: 3, set: true},
+		}, snapshot)
+
+		snapshot, err = r.snapshot(5)
+		require.NoError(t, err)
+		require.Equal(t, map[string]stateSnapshot{
+			"a": {},
+			"b": {value: 3, set: true},
+		}, snapshot)
+
+		_, err = r.snapshot(1)
+		require.Error(t, err)
+
+		_, err = r.snapshot(6)
+		require.Error(t, err)
+	})
+
+	t.Run("snapshot unsets states mutated later", func(t *testing.T) {
+		ctx := makeTestContext()
+		r := newDebugRecorder(3)
+		r.Record(ctx, DebugStateSet, "a", 1, nil)
+		r.Record(ctx, DebugStateSet, "b", 2, nil)
+
+		snapshot, err := r.snapshot(1)
+		require.NoError(t, err)
+		require.Equal(t, map[string]stateSnapshot{
+			"a": {value: 1, set: true},
+			"b": {},
+		}, snapshot)
+	})
+}
+
+func TestDebugInspector(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		debug := newTestEngine().baseContext().Debug()
+		require.False(t, debug.Enabled())
+		require.Empty(t, debug.Events())
+		require.Empty(t, debug.States())
+		require.Error(t, debug.Restore(makeTestContext(), 1))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		EnableDebugRecorder(10)
+		defer func() { debugCapacity = 0 }()
+
+		e := newTestEngine()
+		compo := &hello{}
+		e.Load(compo)
+		ctx := e.nodes.context(e.baseContext(), compo)
+		debug := ctx.Debug()
+		require.True(t, debug.Enabled())
+
+		state := uuid.NewString()
+		var v int
+		ctx.ObserveState(state, &v)
+		ctx.SetState(state, 1)
+		ctx.SetState(state, 2)
+		ctx.NewAction("test")
+		e.ConsumeAll()
+		require.Equal(t, 2, v)
+
+		events := debug.Events()
+		require.Len(t, events, 3)
+		require.Equal(t, DebugStateSet, events[0].Type)
+		require.Equal(t, DebugActionPost, events[2].Type)
+
+		states := debug.States()
+		require.Len(t, states, 1)
+		require.Equal(t, state, states[0].Name)
+		require.Equal(t, 2, states[0].Value)
+		require.Equal(t, []string{"*app.hello"}, states[0].Observers)
+
+		err := debug.Restore(ctx, events[0].ID)
+		require.NoError(t, err)
+		e.ConsumeAll()
+		require.Equal(t, 1, v)
+		require.Len(t, debug.Events(), 3)
+
+		ctx.DelState(state)
+		events = debug.Events()
+		require.Len(t, events, 4)
+		require.Equal(t, DebugStateDelete, events[3].Type)
+	})
+}
+
+func TestDebugOverlay(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		e := newTestEngine()
+		overlay := DebugOverlay().(*debugOverlay)
+		require.NoError(t, e.Load(overlay))
+		e.ConsumeAll()
+		require.False(t, overlay.enabled)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		testSkipNonWasm(t)
+		EnableDebugRecorder(10)
+		defer func() { debugCapacity = 0 }()
+
+		e := newTestEngine()
+		overlay := DebugOverlay().(*debugOverlay)
+		require.NoError(t, e.Load(overlay))
+		e.ConsumeAll()
+		require.True(t, overlay.enabled)
+	})
+
+	t.Run("refresh stops when dismounted", func(t *testing.T) {
+		EnableDebugRecorder(10)
+		defer func() { debugCapacity = 0 }()
+
+		e := newTestEngine()
+		overlay := DebugOverlay().(*debugOverlay)
+		require.NoError(t, e.Load(overlay))
+		e.ConsumeAll()
+
+		ctx := e.nodes.context(e.baseContext(), overlay)
+		overlay.refresh(ctx)
+		require.NotZero(t, overlay.timer)
+
+		require.NoError(t, e.Load(&hello{}))
+		e.ConsumeAll()
+		require.False(t, overlay.Mounted())
+		require.False(t, overlay.timer.Stop())
+
+		overlay.timer = Timer{}
+		overlay.refresh(ctx)
+		require.Zero(t, overlay.timer)
+	})
+
+	t.Run("refresh copies events only when an event is recorded", func(t *testing.T) {
+		EnableDebugRecorder(10)
+		defer func() { debugCapacity = 0 }()
+
+		e := newTestEngine()
+		overlay := DebugOverlay().(*debugOverlay)
+		require.NoError(t, e.Load(overlay))
+		e.ConsumeAll()
+
+		ctx := e.nodes.context(e.baseContext(), overlay)
+		ctx.SetState("state", 42)
+		overlay.refresh(ctx)
+		defer overlay.timer.Stop()
+		require.Len(t, overlay.events, 1)
+		require.Len(t, overlay.states, 1)
+
+		overlay.events = nil
+		overlay.timer.Stop()
+		overlay.refresh(ctx)
+		require.Nil(t, overlay.events)
+
+		ctx.SetState("state", 21)
+		overlay.timer.Stop()
+		overlay.refresh(ctx)
+		require.Len(t, overlay.events, 2)
+	})
+
+	t.Run("render", func(t *testing.T) {
+		overlay := &debugOverlay{
+			enabled:  true,
+			expanded: true,
+			states: []DebugState{
+				{Name: "state", Value: 42, Observers: []string{"*app.hello"}},
+			},
+			events: []DebugEvent{
+				{ID: 1, Type: DebugStateSet, Name: "state", Value: 42},
+				{ID: 2, Type: DebugActionPost, Name: "action"},
+			},
+		}
+		html := HTMLString(overlay)
+		require.Contains(t, html, "1 states, 2 events")
+		require.Contains(t, html, "#1 state-set state 42")
+		require.Contains(t, html, "#2 action-post action")
+	})
+}
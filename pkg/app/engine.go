@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/maxence-charriere/go-app/v11/pkg/errors"
 )
 
@@ -18,6 +19,8 @@ type engineX struct {
 
 	localStorage   BrowserStorage
 	sessionStorage BrowserStorage
+	indexedDB      IndexedDBStorage
+	cookies        BrowserStorage
 	browser        browser
 
 	routes         *router
@@ -36,24 +39,31 @@ type engineX struct {
 
 	asynchronousActionHandlers map[string]ActionHandler
 	actions                    actionManager
+	timers                     timerManager
 	states                     stateManager
+	media                      mediaManager
+	debug                      *debugRecorder
 }
 
 func newEngine(ctx context.Context, routes *router, resolveURL func(string) string, originPage *requestPage, actionHandlers map[string]ActionHandler) *engineX {
 	var localStorage BrowserStorage
 	var sessionStorage BrowserStorage
+	var cookies BrowserStorage
 	if IsServer {
 		localStorage = newMemoryStorage()
 		sessionStorage = newMemoryStorage()
+		cookies = newHTTPCookieStorage(nil)
 	} else {
 		localStorage = newJSStorage("localStorage")
 		sessionStorage = newJSStorage("sessionStorage")
+		cookies = newJSCookieStorage()
 	}
 
 	if resolveURL == nil {
 		resolveURL = func(v string) string { return v }
 	}
 	originPage.resolveURL = resolveURL
+	debug := newDebugRecorder(debugCapacity)
 
 	engine := &engineX{
 		ctx:                        ctx,
@@ -63,10 +73,22 @@ func newEngine(ctx context.Context, routes *router, resolveURL func(string) stri
 		localStorage:               localStorage,
 		lastVisitedURL:             &url.URL{},
 		sessionStorage:             sessionStorage,
+		indexedDB:                  newIndexedDBStorage(indexedDBName),
+		cookies:                    cookies,
 		nodes:                      nodeManager{},
 		dispatches:                 make(chan func(), 4096),
 		defers:                     make(chan func(), 4096),
 		asynchronousActionHandlers: actionHandlers,
+		actions: actionManager{
+			middlewares: actionMiddlewares,
+			debug:       debug,
+		},
+		states: stateManager{
+			configs:          stateConfigs,
+			broadcastStoreID: uuid.NewString(),
+			debug:            debug,
+		},
+		debug: debug,
 	}
 
 	engine.initBrowser()
@@ -82,9 +104,14 @@ func (e *engineX) baseContext() Context {
 		navigate:              e.Navigate,
 		localStorage:          e.localStorage,
 		sessionStorage:        e.sessionStorage,
+		indexedDB:             e.indexedDB,
+		cookies:               e.cookies,
 		dispatch:              e.dispatch,
 		defere:                e.defere,
 		async:                 e.async,
+		afterFunc:             e.timers.AfterFunc,
+		debounce:              e.timers.Debounce,
+		throttle:              e.timers.Throttle,
 		addComponentUpdate:    e.updates.Add,
 		removeComponentUpdate: e.updates.Done,
 		handleAction:          e.actions.Handle,
@@ -93,11 +120,30 @@ func (e *engineX) baseContext() Context {
 		getState:              e.states.Get,
 		setState:              e.states.Set,
 		delState:              e.states.Delete,
+		deriveState:           e.states.Derive,
+		stateTransaction:      e.states.Transaction,
+		undo:                  e.states.Undo,
+		redo:                  e.states.Redo,
+		canUndo:               e.states.CanUndo,
+		canRedo:               e.states.CanRedo,
+		matchMedia:            e.media.Match,
+		debug:                 e.debugInspector(),
+		catchPanic:            e.nodes.CatchPanic,
 
 		notifyComponentEvent: e.nodes.NotifyComponentEvent,
 	}
 }
 
+func (e *engineX) debugInspector() DebugInspector {
+	if e.debug == nil {
+		return DebugInspector{}
+	}
+	return DebugInspector{
+		recorder: e.debug,
+		states:   &e.states,
+	}
+}
+
 // Navigate directs the engine to the specified URL destination, which might be
 // an internal page within the app, an external link outside the app, or a
 // mailto link. If the 'updateHistory' flag is true, the destination is added to
@@ -162,6 +208,11 @@ func (e *engineX) initBrowser() {
 		return
 	}
 	e.browser.HandleEvents(e.baseContext(), e.notifyComponentEvent)
+	e.indexedDB.Load(func(err error) {
+		if err != nil {
+			Log(err)
+		}
+	})
 }
 
 func (e *engineX) notifyComponentEvent(event any) {
@@ -265,12 +316,16 @@ func (e *engineX) processFrame() {
 		}
 
 		if _, err := e.nodes.UpdateComponentRoot(e.baseContext(), c); err != nil {
-			panic(errors.New("updating component failed").Wrap(err))
+			if err = e.nodes.CatchError(e.baseContext(), c, err); err != nil {
+				panic(errors.New("updating component failed").Wrap(err))
+			}
 		}
 	})
 	e.executeDefers()
 	e.actions.Cleanup()
 	e.states.Cleanup()
+	e.timers.Cleanup()
+	e.media.Cleanup()
 }
 
 func (e *engineX) executeDefers() {
@@ -207,6 +207,53 @@ func TestEngineAsync(t *testing.T) {
 	require.True(t, called)
 }
 
+func TestEngineErrorBoundary(t *testing.T) {
+	t.Run("dispatch panic is caught", func(t *testing.T) {
+		e := newTestEngine()
+		child := &hello{}
+		boundary := &errorBoundaryComponent{Child: child}
+		require.NoError(t, e.Load(boundary))
+		e.ConsumeAll()
+
+		ctx := e.nodes.context(e.baseContext(), child)
+		ctx.Dispatch(func(ctx Context) {
+			panic("handler panic")
+		})
+		e.ConsumeAll()
+		require.Error(t, boundary.err)
+		require.IsType(t, P(), boundary.root())
+	})
+
+	t.Run("dispatch panic without boundary continues", func(t *testing.T) {
+		e := newTestEngine()
+		child := &hello{}
+		require.NoError(t, e.Load(child))
+		e.ConsumeAll()
+
+		ctx := e.nodes.context(e.baseContext(), child)
+		ctx.Dispatch(func(ctx Context) {
+			panic("handler panic")
+		})
+		require.Panics(t, e.ConsumeAll)
+	})
+
+	t.Run("update failure is caught", func(t *testing.T) {
+		e := newTestEngine()
+		child := &panicComponent{}
+		boundary := &errorBoundaryComponent{Child: child}
+		require.NoError(t, e.Load(boundary))
+		e.ConsumeAll()
+
+		ctx := e.nodes.context(e.baseContext(), child)
+		ctx.Dispatch(func(ctx Context) {
+			child.Panic = true
+		})
+		e.ConsumeAll()
+		require.Error(t, boundary.err)
+		require.IsType(t, P(), boundary.root())
+	})
+}
+
 func TestEngineStart(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -0,0 +1,163 @@
+package app
+
+import (
+	"sync"
+)
+
+const (
+	defaultStateHistoryLimit = 100
+)
+
+// SetStateHistoryLimit sets the maximum number of undo steps kept for the
+// states tracked with StateConfig.TrackHistory. Older steps are discarded
+// first. Defaults to 100.
+func SetStateHistoryLimit(v int) {
+	stateConfigs.setHistoryLimit(v)
+}
+
+// stateSnapshot is the value of a state at a given point in time.
+type stateSnapshot struct {
+	value any
+	set   bool
+}
+
+// stateChange describes the modification of a history-tracked state.
+type stateChange struct {
+	state  string
+	before stateSnapshot
+	after  stateSnapshot
+}
+
+// stateHistory stores the undo and redo steps of history-tracked states. A
+// step groups the changes made within a transaction, or a single change when
+// made outside of a transaction.
+type stateHistory struct {
+	mutex       sync.Mutex
+	undo        [][]stateChange
+	redo        [][]stateChange
+	transaction []stateChange
+	depth       int
+}
+
+// historySnapshot returns the current value of the given state and reports
+// whether its changes are tracked.
+func (m *stateManager) historySnapshot(state string) (stateSnapshot, bool) {
+	if !m.configs.get(state).trackHistory {
+		return stateSnapshot{}, false
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	value, ok := m.states[state]
+	return stateSnapshot{
+		value: value.value,
+		set:   ok,
+	}, true
+}
+
+func (m *stateManager) trackChange(c stateChange) {
+	m.history.mutex.Lock()
+	defer m.history.mutex.Unlock()
+
+	if m.history.depth > 0 {
+		m.history.transaction = append(m.history.transaction, c)
+		return
+	}
+	m.pushUndo([]stateChange{c})
+	m.history.redo = nil
+}
+
+func (m *stateManager) pushUndo(step []stateChange) {
+	m.history.undo = append(m.history.undo, step)
+	if limit := m.configs.getHistoryLimit(); len(m.history.undo) > limit {
+		m.history.undo = m.history.undo[len(m.history.undo)-limit:]
+	}
+}
+
+// Transaction executes the given function and groups the changes of the
+// history-tracked states it makes into a single undo step. Transactions can be
+// nested, the outermost one creating the undo step.
+func (m *stateManager) Transaction(ctx Context, f func(Context)) {
+	m.history.mutex.Lock()
+	m.history.depth++
+	m.history.mutex.Unlock()
+
+	defer func() {
+		m.history.mutex.Lock()
+		defer m.history.mutex.Unlock()
+
+		m.history.depth--
+		if m.history.depth > 0 || len(m.history.transaction) == 0 {
+			return
+		}
+		m.pushUndo(m.history.transaction)
+		m.history.transaction = nil
+		m.history.redo = nil
+	}()
+
+	f(ctx)
+}
+
+// Undo reverts the last undo step and makes it available to Redo.
+func (m *stateManager) Undo(ctx Context) {
+	m.history.mutex.Lock()
+	n := len(m.history.undo)
+	if n == 0 {
+		m.history.mutex.Unlock()
+		return
+	}
+	step := m.history.undo[n-1]
+	m.history.undo = m.history.undo[:n-1]
+	m.history.redo = append(m.history.redo, step)
+	m.history.mutex.Unlock()
+
+	for i := len(step) - 1; i >= 0; i-- {
+		m.applySnapshot(ctx, step[i].state, step[i].before)
+	}
+}
+
+// Redo reapplies the last undo step reverted by Undo.
+func (m *stateManager) Redo(ctx Context) {
+	m.history.mutex.Lock()
+	n := len(m.history.redo)
+	if n == 0 {
+		m.history.mutex.Unlock()
+		return
+	}
+	step := m.history.redo[n-1]
+	m.history.redo = m.history.redo[:n-1]
+	m.pushUndo(step)
+	m.history.mutex.Unlock()
+
+	for _, c := range step {
+		m.applySnapshot(ctx, c.state, c.after)
+	}
+}
+
+// CanUndo reports whether there is an undo step.
+func (m *stateManager) CanUndo() bool {
+	m.history.mutex.Lock()
+	defer m.history.mutex.Unlock()
+	return len(m.history.undo) != 0
+}
+
+// CanRedo reports whether there is a step reverted by Undo that can be
+// reapplied.
+func (m *stateManager) CanRedo() bool {
+	m.history.mutex.Lock()
+	defer m.history.mutex.Unlock()
+	return len(m.history.redo) != 0
+}
+
+// applySnapshot sets the in-memory value of the given state and notifies its
+// observers, without tracking the change.
+func (m *stateManager) applySnapshot(ctx Context, state string, v stateSnapshot) {
+	m.set(ctx, state, v.value)
+	if !v.set {
+		m.mutex.Lock()
+		delete(m.states, state)
+		m.mutex.Unlock()
+	}
+	m.invalidateDependents(ctx, state)
+}
@@ -0,0 +1,188 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetStateHistoryLimit(t *testing.T) {
+	defer SetStateHistoryLimit(0)
+
+	require.Equal(t, defaultStateHistoryLimit, stateConfigs.getHistoryLimit())
+	SetStateHistoryLimit(42)
+	require.Equal(t, 42, stateConfigs.getHistoryLimit())
+
+	var registry *stateConfigRegistry
+	require.Equal(t, defaultStateHistoryLimit, registry.getHistoryLimit())
+}
+
+func TestStateManagerHistory(t *testing.T) {
+	newTrackedState := func(t *testing.T) string {
+		state := uuid.NewString()
+		ConfigureState(state).TrackHistory()
+		t.Cleanup(func() { delete(stateConfigs.configs, state) })
+		return state
+	}
+
+	t.Run("untracked state has no history", func(t *testing.T) {
+		var m stateManager
+		ctx := makeTestContext()
+
+		m.Set(ctx, uuid.NewString(), 42)
+		require.False(t, m.CanUndo())
+	})
+
+	t.Run("undo and redo", func(t *testing.T) {
+		state := newTrackedState(t)
+
+		m := stateManager{configs: stateConfigs}
+		ctx := makeTestContext()
+
+		m.Set(ctx, state, 1)
+		m.Set(ctx, state, 2)
+		require.True(t, m.CanUndo())
+		require.False(t, m.CanRedo())
+
+		var v int
+		m.Undo(ctx)
+		m.Get(ctx, state, &v)
+		require.Equal(t, 1, v)
+		require.True(t, m.CanRedo())
+
+		m.Undo(ctx)
+		require.NotContains(t, m.states, state)
+		require.False(t, m.CanUndo())
+		m.Undo(ctx)
+
+		m.Redo(ctx)
+		m.Redo(ctx)
+		m.Get(ctx, state, &v)
+		require.Equal(t, 2, v)
+		require.False(t, m.CanRedo())
+		m.Redo(ctx)
+	})
+
+	t.Run("change clears redo", func(t *testing.T) {
+		state := newTrackedState(t)
+
+		m := stateManager{configs: stateConfigs}
+		ctx := makeTestContext()
+
+		m.Set(ctx, state, 1)
+		m.Set(ctx, state, 2)
+		m.Undo(ctx)
+		require.True(t, m.CanRedo())
+
+		m.Set(ctx, state, 3)
+		require.False(t, m.CanRedo())
+	})
+
+	t.Run("delete is undone", func(t *testing.T) {
+		state := newTrackedState(t)
+
+		m := stateManager{configs: stateConfigs}
+		ctx := makeTestContext()
+
+		m.Set(ctx, state, 42)
+		m.Delete(ctx, state)
+		require.NotContains(t, m.states, state)
+
+		var v int
+		m.Undo(ctx)
+		m.Get(ctx, state, &v)
+		require.Equal(t, 42, v)
+	})
+
+	t.Run("transaction is a single step", func(t *testing.T) {
+		state1 := newTrackedState(t)
+		state2 := newTrackedState(t)
+
+		m := stateManager{configs: stateConfigs}
+		ctx := makeTestContext()
+
+		m.Set(ctx, state1, 1)
+		m.Transaction(ctx, func(ctx Context) {
+			m.Set(ctx, state1, 2)
+			m.Transaction(ctx, func(ctx Context) {
+				m.Set(ctx, state2, 3)
+			})
+			require.Len(t, m.history.undo, 1)
+		})
+		require.Len(t, m.history.undo, 2)
+
+		var v1, v2 int
+		m.Undo(ctx)
+		m.Get(ctx, state1, &v1)
+		require.Equal(t, 1, v1)
+		require.NotContains(t, m.states, state2)
+
+		m.Redo(ctx)
+		m.Get(ctx, state1, &v1)
+		m.Get(ctx, state2, &v2)
+		require.Equal(t, 2, v1)
+		require.Equal(t, 3, v2)
+	})
+
+	t.Run("empty transaction has no step", func(t *testing.T) {
+		var m stateManager
+		ctx := makeTestContext()
+
+		m.Transaction(ctx, func(ctx Context) {})
+		require.False(t, m.CanUndo())
+	})
+
+	t.Run("history is bounded", func(t *testing.T) {
+		SetStateHistoryLimit(2)
+		defer SetStateHistoryLimit(0)
+		state := newTrackedState(t)
+
+		m := stateManager{configs: stateConfigs}
+		ctx := makeTestContext()
+
+		m.Set(ctx, state, 1)
+		m.Set(ctx, state, 2)
+		m.Set(ctx, state, 3)
+		require.Len(t, m.history.undo, 2)
+
+		var v int
+		m.Undo(ctx)
+		m.Undo(ctx)
+		m.Get(ctx, state, &v)
+		require.Equal(t, 1, v)
+		require.False(t, m.CanUndo())
+	})
+}
+
+func TestContextUndoRedo(t *testing.T) {
+	state := uuid.NewString()
+	ConfigureState(state).TrackHistory()
+	defer delete(stateConfigs.configs, state)
+
+	e := newTestEngine()
+	hello := &hello{}
+	e.Load(hello)
+	ctx := e.nodes.context(e.baseContext(), hello)
+
+	var v int
+	ctx.ObserveState(state, &v)
+	ctx.StateTransaction(func(ctx Context) {
+		ctx.SetState(state, 1)
+		ctx.SetState(state, 2)
+	})
+	e.ConsumeAll()
+	require.Equal(t, 2, v)
+	require.True(t, ctx.CanUndo())
+
+	ctx.Undo()
+	e.ConsumeAll()
+	require.Zero(t, v)
+	require.False(t, ctx.CanUndo())
+	require.True(t, ctx.CanRedo())
+
+	ctx.Redo()
+	e.ConsumeAll()
+	require.Equal(t, 2, v)
+	require.False(t, ctx.CanRedo())
+}
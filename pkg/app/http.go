@@ -150,6 +150,8 @@ type Handler struct {
 	once                 sync.Once
 	etag                 string
 	libraries            map[string][]byte
+	componentStyles      []byte
+	bundledStyles        int
 	proxyResources       map[string]ProxyResource
 	cachedProxyResources *memoryCache
 	cachedPWAResources   *memoryCache
@@ -159,6 +161,7 @@ func (h *Handler) init() {
 	h.initVersion()
 	h.initStaticResources()
 	h.initLibraries()
+	h.initComponentStyles()
 	h.initLinks()
 	h.initServiceWorker()
 	h.initIcon()
@@ -194,8 +197,23 @@ func (h *Handler) initLibraries() {
 	h.libraries = libs
 }
 
+// initComponentStyles builds the stylesheet made of the scoped styles of the
+// routed components. The styles of the other components are collected when
+// they are rendered and embedded in the pages.
+func (h *Handler) initComponentStyles() {
+	for _, c := range routes.createComponents() {
+		if styler, ok := c.(Styler); ok {
+			componentStyles.Register(styler)
+		}
+	}
+
+	h.componentStyles = componentStyles.Bundle(0)
+	h.bundledStyles = componentStyles.Len()
+}
+
 func (h *Handler) initLinks() {
-	styles := []string{"/app.css"}
+	componentStylesURL := fmt.Sprintf("%s?v=%x", componentStylesPath, sha1.Sum(h.componentStyles))
+	styles := []string{"/app.css", componentStylesURL}
 	for path := range h.libraries {
 		styles = append(styles, path)
 	}
@@ -535,6 +553,11 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if path == componentStylesPath {
+		h.serveLibrary(w, r, h.componentStyles)
+		return
+	}
+
 	h.servePage(w, r)
 }
 
@@ -628,6 +651,7 @@ func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
 	page.SetKeywords(h.Keywords...)
 	page.SetLoadingLabel(strings.ReplaceAll(h.LoadingLabel, "{progress}", "0"))
 	page.SetImage(h.Image)
+	page.width, page.height = viewportHints(r)
 
 	engine := newEngine(ctx,
 		&routes,
@@ -635,8 +659,11 @@ func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
 		&page,
 		actionHandlers,
 	)
+	cookies := newHTTPCookieStorage(r)
+	engine.cookies = cookies
 	engine.Navigate(page.URL(), false)
 	engine.ConsumeAll()
+	pageStyles := componentStyles.Bundle(h.bundledStyles)
 
 	icon := h.Icon.SVG
 	if icon == "" {
@@ -749,6 +776,9 @@ func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
 					}
 					return nil
 				}),
+				If(len(pageStyles) != 0, func() UI {
+					return Raw("<style>\n" + string(pageStyles) + "</style>")
+				}),
 				Script().
 					Defer(true).
 					Src("/wasm_exec.js"),
@@ -789,6 +819,9 @@ func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	cookies.setResponseCookies(w)
+	w.Header().Set("Accept-CH", viewportClientHints)
+	w.Header().Add("Vary", viewportClientHints+", Sec-CH-UA-Mobile")
 	w.Header().Set("Content-Length", strconv.Itoa(b.Len()))
 	w.Header().Set("Content-Type", "text/html")
 	w.Write(b.Bytes())
@@ -0,0 +1,427 @@
+package app
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/maxence-charriere/go-app/v11/pkg/errors"
+)
+
+const (
+	indexedDBName      = "goapp"
+	indexedDBStoreName = "items"
+)
+
+var (
+	// ErrIndexedDBNotLoaded is the error returned when an IndexedDB storage
+	// item is read before the storage items are loaded.
+	ErrIndexedDBNotLoaded = errors.New("indexeddb storage is not loaded")
+)
+
+// IndexedDBStorage is a BrowserStorage backed by an IndexedDB object store.
+//
+// IndexedDB is not subject to the few megabytes limit of localStorage and
+// sessionStorage. Items are loaded asynchronously in memory when the storage
+// is first accessed, which allows the BrowserStorage methods to remain
+// synchronous, while changes are written asynchronously to the database.
+//
+// Until the items are loaded, Get returns ErrIndexedDBNotLoaded and the other
+// methods only report the items changed since the storage was first accessed.
+// Changes made before the items are loaded are applied over them. Load can be
+// used to wait for the items to be loaded.
+//
+// When IndexedDB is not available, such as during server-side rendering,
+// items are only kept in memory.
+type IndexedDBStorage interface {
+	BrowserStorage
+
+	// Load loads the storage items in memory. The done function is called on a
+	// separate goroutine once the items are loaded or have failed to load.
+	Load(done func(error))
+
+	// SetMany stores the given items within a single transaction. The values
+	// must be capable of being converted to JSON format. The done function is
+	// called on a separate goroutine once the transaction is committed or has
+	// failed.
+	SetMany(items map[string]any, done func(error))
+
+	// DelMany removes the items associated with the given keys within a single
+	// transaction. The done function is called on a separate goroutine once
+	// the transaction is committed or has failed.
+	DelMany(keys []string, done func(error))
+
+	// Estimate reports the number of bytes used by the origin and the quota
+	// granted by the browser. The done function is called on a separate
+	// goroutine.
+	Estimate(done func(usage, quota int64, err error))
+}
+
+type indexedDBStorage struct {
+	name     string
+	mutex    sync.RWMutex
+	data     map[string][]byte
+	openOnce sync.Once
+	loaded   bool
+	loads    []func(error)
+	pending  []indexedDBChange
+	db       Value
+	err      error
+}
+
+// indexedDBChange is a change made before the storage items are loaded.
+type indexedDBChange struct {
+	apply func(data map[string][]byte)
+	write func(store Value)
+	done  func(error)
+}
+
+func newIndexedDBStorage(name string) *indexedDBStorage {
+	return &indexedDBStorage{
+		name: name,
+		data: make(map[string][]byte),
+	}
+}
+
+func (s *indexedDBStorage) Load(done func(error)) {
+	s.open()
+
+	s.mutex.Lock()
+	if !s.loaded {
+		s.loads = append(s.loads, done)
+		s.mutex.Unlock()
+		return
+	}
+	err := s.err
+	s.mutex.Unlock()
+
+	go done(err)
+}
+
+func (s *indexedDBStorage) Set(k string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	s.change(func(data map[string][]byte) {
+		data[k] = b
+	}, func(store Value) {
+		store.Call("put", string(b), k)
+	}, func(err error) {
+		if err != nil {
+			Log(errors.New("setting indexeddb value failed").
+				WithTag("key", k).
+				Wrap(err))
+		}
+	})
+	return nil
+}
+
+func (s *indexedDBStorage) SetMany(items map[string]any, done func(error)) {
+	values := make(map[string][]byte, len(items))
+	for k, v := range items {
+		b, err := json.Marshal(v)
+		if err != nil {
+			go done(errors.New("encoding indexeddb value failed").
+				WithTag("key", k).
+				Wrap(err))
+			return
+		}
+		values[k] = b
+	}
+
+	s.change(func(data map[string][]byte) {
+		for k, b := range values {
+			data[k] = b
+		}
+	}, func(store Value) {
+		for k, b := range values {
+			store.Call("put", string(b), k)
+		}
+	}, done)
+}
+
+func (s *indexedDBStorage) Get(k string, v any) error {
+	s.open()
+	s.mutex.RLock()
+	loaded := s.loaded
+	b, ok := s.data[k]
+	s.mutex.RUnlock()
+	if !loaded {
+		return ErrIndexedDBNotLoaded
+	}
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(b, v)
+}
+
+func (s *indexedDBStorage) Del(k string) {
+	s.DelMany([]string{k}, func(err error) {
+		if err != nil {
+			Log(errors.New("deleting indexeddb value failed").
+				WithTag("key", k).
+				Wrap(err))
+		}
+	})
+}
+
+func (s *indexedDBStorage) DelMany(keys []string, done func(error)) {
+	s.change(func(data map[string][]byte) {
+		for _, k := range keys {
+			delete(data, k)
+		}
+	}, func(store Value) {
+		for _, k := range keys {
+			store.Call("delete", k)
+		}
+	}, done)
+}
+
+func (s *indexedDBStorage) Len() int {
+	s.open()
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.data)
+}
+
+func (s *indexedDBStorage) Clear() {
+	s.change(func(data map[string][]byte) {
+		clear(data)
+	}, func(store Value) {
+		store.Call("clear")
+	}, func(err error) {
+		if err != nil {
+			Log(errors.New("clearing indexeddb failed").Wrap(err))
+		}
+	})
+}
+
+func (s *indexedDBStorage) ForEach(f func(k string)) {
+	s.open()
+	s.mutex.RLock()
+	keys := make([]string, 0, len(s.data))
+	for k := range s.data {
+		keys = append(keys, k)
+	}
+	s.mutex.RUnlock()
+
+	for _, k := range keys {
+		f(k)
+	}
+}
+
+func (s *indexedDBStorage) Contains(k string) bool {
+	s.open()
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	_, ok := s.data[k]
+	return ok
+}
+
+func (s *indexedDBStorage) Estimate(done func(usage, quota int64, err error)) {
+	storage := Window().Get("navigator").Get("storage")
+	if IsServer || !storage.Truthy() || !storage.Get("estimate").Truthy() {
+		go done(0, 0, errors.New("storage estimate not supported"))
+		return
+	}
+
+	var fulfilled, rejected Func
+	release := func() {
+		fulfilled.Release()
+		rejected.Release()
+	}
+	fulfilled = FuncOf(func(this Value, args []Value) any {
+		release()
+		estimate := args[0]
+		go done(int64(estimate.Get("usage").Float()), int64(estimate.Get("quota").Float()), nil)
+		return nil
+	})
+	rejected = FuncOf(func(this Value, args []Value) any {
+		release()
+		err := errors.New("estimating storage failed")
+		if len(args) != 0 && args[0].Truthy() {
+			err = err.WithTag("reason", args[0].Call("toString").String())
+		}
+		go done(0, 0, err)
+		return nil
+	})
+	storage.Call("estimate").Call("then", fulfilled, rejected)
+}
+
+// open starts loading the database items in memory. It does not block: the
+// items are loaded asynchronously.
+func (s *indexedDBStorage) open() {
+	s.openOnce.Do(func() {
+		if IsServer {
+			s.finishLoading(nil, nil, nil)
+			return
+		}
+
+		factory := Window().Get("indexedDB")
+		if !factory.Truthy() {
+			s.finishLoading(nil, nil, errors.New("indexeddb not supported"))
+			return
+		}
+
+		request := factory.Call("open", s.name, 1)
+		upgrade := FuncOf(func(this Value, args []Value) any {
+			request.Get("result").Call("createObjectStore", indexedDBStoreName)
+			return nil
+		})
+		request.Set("onupgradeneeded", upgrade)
+
+		handleIndexedDBRequest(request, func(db Value, err error) {
+			upgrade.Release()
+			if err != nil {
+				s.finishLoading(nil, nil, errors.New("opening indexeddb failed").
+					WithTag("name", s.name).
+					Wrap(err))
+				return
+			}
+
+			s.load(db, func(data map[string][]byte, err error) {
+				if err != nil {
+					err = errors.New("loading indexeddb items failed").
+						WithTag("name", s.name).
+						Wrap(err)
+				}
+				s.finishLoading(db, data, err)
+			})
+		})
+	})
+}
+
+func (s *indexedDBStorage) load(db Value, done func(map[string][]byte, error)) {
+	store := db.
+		Call("transaction", indexedDBStoreName, "readonly").
+		Call("objectStore", indexedDBStoreName)
+
+	handleIndexedDBRequest(store.Call("getAllKeys"), func(keys Value, err error) {
+		if err != nil {
+			done(nil, err)
+			return
+		}
+
+		handleIndexedDBRequest(store.Call("getAll"), func(values Value, err error) {
+			if err != nil {
+				done(nil, err)
+				return
+			}
+
+			data := make(map[string][]byte, keys.Length())
+			for i := 0; i < keys.Length(); i++ {
+				data[keys.Index(i).String()] = []byte(values.Index(i).String())
+			}
+			done(data, nil)
+		})
+	})
+}
+
+// finishLoading marks the storage items as loaded with the given database
+// items, applies the changes made while they were loading over them and writes
+// these changes to the database.
+func (s *indexedDBStorage) finishLoading(db Value, data map[string][]byte, err error) {
+	s.mutex.Lock()
+	s.db = db
+	s.err = err
+	if data != nil {
+		s.data = data
+	}
+	for _, c := range s.pending {
+		c.apply(s.data)
+	}
+	s.loaded = true
+	pending := s.pending
+	s.pending = nil
+	loads := s.loads
+	s.loads = nil
+	s.mutex.Unlock()
+
+	for _, c := range pending {
+		s.write(c.write, c.done)
+	}
+	for _, done := range loads {
+		go done(err)
+	}
+}
+
+// change applies the given change to the items in memory and writes it to the
+// database, once the items are loaded.
+func (s *indexedDBStorage) change(apply func(map[string][]byte), write func(Value), done func(error)) {
+	s.open()
+
+	s.mutex.Lock()
+	apply(s.data)
+	if !s.loaded {
+		s.pending = append(s.pending, indexedDBChange{
+			apply: apply,
+			write: write,
+			done:  done,
+		})
+		s.mutex.Unlock()
+		return
+	}
+	s.mutex.Unlock()
+
+	s.write(write, done)
+}
+
+func (s *indexedDBStorage) write(f func(store Value), done func(error)) {
+	if s.db == nil {
+		go done(s.err)
+		return
+	}
+
+	transaction := s.db.Call("transaction", indexedDBStoreName, "readwrite")
+	var complete, failure Func
+	release := func() {
+		complete.Release()
+		failure.Release()
+	}
+	complete = FuncOf(func(this Value, args []Value) any {
+		release()
+		go done(nil)
+		return nil
+	})
+	failure = FuncOf(func(this Value, args []Value) any {
+		release()
+		go done(indexedDBError(transaction))
+		return nil
+	})
+	transaction.Set("oncomplete", complete)
+	transaction.Set("onerror", failure)
+	transaction.Set("onabort", failure)
+
+	f(transaction.Call("objectStore", indexedDBStoreName))
+}
+
+func handleIndexedDBRequest(request Value, f func(result Value, err error)) {
+	var success, failure Func
+	release := func() {
+		success.Release()
+		failure.Release()
+	}
+	success = FuncOf(func(this Value, args []Value) any {
+		release()
+		f(request.Get("result"), nil)
+		return nil
+	})
+	failure = FuncOf(func(this Value, args []Value) any {
+		release()
+		f(nil, indexedDBError(request))
+		return nil
+	})
+	request.Set("onsuccess", success)
+	request.Set("onerror", failure)
+}
+
+func indexedDBError(v Value) error {
+	err := errors.New("indexeddb operation failed")
+	if jsErr := v.Get("error"); jsErr.Truthy() {
+		err = err.
+			WithTag("name", jsErr.Get("name").String()).
+			WithTag("message", jsErr.Get("message").String())
+	}
+	return err
+}
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maxence-charriere/go-app/v11/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIndexedDBStorage(t *testing.T) *indexedDBStorage {
+	s := newIndexedDBStorage("goapp-test-" + uuid.NewString())
+
+	errs := make(chan error, 1)
+	s.Load(func(err error) {
+		errs <- err
+	})
+	require.NoError(t, <-errs)
+	return s
+}
+
+func TestIndexedDBStorage(t *testing.T) {
+	testBrowserStorage(t, newTestIndexedDBStorage(t))
+}
+
+func TestIndexedDBStorageLoading(t *testing.T) {
+	testSkipWasm(t)
+
+	s := newIndexedDBStorage("goapp-test-" + uuid.NewString())
+	s.openOnce.Do(func() {})
+
+	loaded := make(chan error, 1)
+	s.Load(func(err error) {
+		loaded <- err
+	})
+
+	var v int
+	require.True(t, errors.Is(s.Get("/hello", &v), ErrIndexedDBNotLoaded))
+
+	require.NoError(t, s.Set("/hello", 42))
+	s.Del("/bye")
+	require.Len(t, s.pending, 2)
+
+	s.finishLoading(nil, map[string][]byte{
+		"/bye":   []byte("21"),
+		"/world": []byte("84"),
+	}, nil)
+	require.NoError(t, <-loaded)
+	require.Empty(t, s.pending)
+
+	require.NoError(t, s.Get("/hello", &v))
+	require.Equal(t, 42, v)
+	require.NoError(t, s.Get("/world", &v))
+	require.Equal(t, 84, v)
+	require.False(t, s.Contains("/bye"))
+}
+
+func TestIndexedDBStorageSetMany(t *testing.T) {
+	s := newTestIndexedDBStorage(t)
+
+	errs := make(chan error, 1)
+	s.SetMany(map[string]any{
+		"/hello": obj{Foo: 42, Bar: "hello"},
+		"/world": obj{Foo: 21, Bar: "world"},
+	}, func(err error) {
+		errs <- err
+	})
+	require.NoError(t, <-errs)
+	require.Equal(t, 2, s.Len())
+
+	var o obj
+	err := s.Get("/world", &o)
+	require.NoError(t, err)
+	require.Equal(t, 21, o.Foo)
+	require.Equal(t, "world", o.Bar)
+}
+
+func TestIndexedDBStorageSetManyError(t *testing.T) {
+	s := newIndexedDBStorage("goapp-test-" + uuid.NewString())
+
+	errs := make(chan error, 1)
+	s.SetMany(map[string]any{
+		"/func": func() {},
+	}, func(err error) {
+		errs <- err
+	})
+	require.Error(t, <-errs)
+	require.Zero(t, s.Len())
+}
+
+func TestIndexedDBStorageDelMany(t *testing.T) {
+	s := newTestIndexedDBStorage(t)
+	s.Set("/hello", 42)
+	s.Set("/world", 42)
+	s.Set("/bye", 42)
+
+	errs := make(chan error, 1)
+	s.DelMany([]string{"/hello", "/world"}, func(err error) {
+		errs <- err
+	})
+	require.NoError(t, <-errs)
+	require.Equal(t, 1, s.Len())
+	require.True(t, s.Contains("/bye"))
+}
+
+func TestIndexedDBStorageEstimate(t *testing.T) {
+	testSkipWasm(t)
+
+	errs := make(chan error, 1)
+	newIndexedDBStorage("goapp-test").Estimate(func(usage, quota int64, err error) {
+		errs <- err
+	})
+	require.Error(t, <-errs)
+}
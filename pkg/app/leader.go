@@ -0,0 +1,210 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maxence-charriere/go-app/v11/pkg/errors"
+)
+
+const (
+	leaderLockPrefix       = "go-app-leader/"
+	leaderStorageKeyPrefix = "/go-app/leader/"
+	leaderHeartbeat        = time.Second
+
+	// leaderTTL is longer than the one minute interval at which browsers can
+	// throttle the timers of background tabs, so that a throttled leader keeps
+	// renewing its lease in time.
+	leaderTTL = 2 * time.Minute
+)
+
+func requestLeadership(ctx Context, name string, f func(Context)) {
+	if IsServer {
+		return
+	}
+
+	if locks := Window().Get("navigator").Get("locks"); locks.Truthy() {
+		requestLeadershipLock(ctx, locks, name, f)
+		return
+	}
+
+	election := &leaderElection{
+		key:     leaderStorageKeyPrefix + name,
+		id:      uuid.NewString(),
+		storage: ctx.LocalStorage(),
+	}
+
+	// The lease is released when the page is hidden, so that another tab
+	// takes over without waiting for its expiration.
+	resign := FuncOf(func(this Value, args []Value) any {
+		election.resign()
+		return nil
+	})
+	Window().addEventListener("pagehide", resign, nil)
+
+	ctx.Async(func() {
+		defer resign.Release()
+		defer Window().removeEventListener("pagehide", resign)
+		election.run(ctx, f)
+	})
+}
+
+// requestLeadershipLock elects the leader with the Web Locks API. The lock is
+// held by returning a promise that is resolved when the context is done. As
+// with the lease, the given function is dispatched with a context that is
+// cancelled when the leadership is lost, which includes the lock being stolen.
+func requestLeadershipLock(ctx Context, locks Value, name string, f func(Context)) {
+	leaderCtx := ctx
+	var cancel context.CancelFunc
+	leaderCtx.Context, cancel = context.WithCancel(ctx.Context)
+
+	var acquire, settle Func
+	acquire = FuncOf(func(this Value, args []Value) any {
+		acquire.Release()
+
+		var executor Func
+		executor = FuncOf(func(this Value, args []Value) any {
+			executor.Release()
+			release := args[0]
+			go func() {
+				<-leaderCtx.Done()
+				release.Invoke()
+			}()
+			return nil
+		})
+
+		leaderCtx.Dispatch(f)
+		return Window().Get("Promise").New(executor)
+	})
+	settle = FuncOf(func(this Value, args []Value) any {
+		acquire.Release()
+		settle.Release()
+		cancel()
+		return nil
+	})
+
+	locks.
+		Call("request", leaderLockPrefix+name, acquire).
+		Call("then", settle, settle)
+}
+
+// leaderLease is the local storage record of the current leader when the Web
+// Locks API is not available.
+type leaderLease struct {
+	ID        string
+	ExpiresAt time.Time
+}
+
+// leaderElection elects the leader with a heartbeat written in local storage.
+// A tab claims the leadership when there is no lease or when it is expired,
+// and becomes the leader when its claim is still in place at the next
+// heartbeat.
+type leaderElection struct {
+	key     string
+	id      string
+	storage BrowserStorage
+
+	mutex  sync.Mutex
+	leader bool
+	cancel func()
+}
+
+func (e *leaderElection) run(ctx Context, f func(Context)) {
+	ticker := time.NewTicker(leaderHeartbeat)
+	defer ticker.Stop()
+	defer e.resign()
+
+	for {
+		e.step(ctx, f, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// step runs an election round. The given function is dispatched with a context
+// that is cancelled when the leadership is lost.
+func (e *leaderElection) step(ctx Context, f func(Context), now time.Time) {
+	e.mutex.Lock()
+	wasLeader := e.leader
+	acquired := e.tick(now)
+	switch {
+	case acquired:
+		ctx.Context, e.cancel = context.WithCancel(ctx.Context)
+
+	case wasLeader && !e.leader:
+		e.cancelLeadership()
+	}
+	e.mutex.Unlock()
+
+	if acquired {
+		ctx.Dispatch(f)
+	}
+}
+
+func (e *leaderElection) cancelLeadership() {
+	if e.cancel != nil {
+		e.cancel()
+		e.cancel = nil
+	}
+}
+
+// tick renews or claims the lease. It reports whether the leadership has just
+// been acquired.
+func (e *leaderElection) tick(now time.Time) bool {
+	var lease leaderLease
+	if err := e.storage.Get(e.key, &lease); err != nil {
+		Log(errors.New("reading leader lease failed").
+			WithTag("key", e.key).
+			Wrap(err))
+	}
+
+	switch {
+	case lease.ID == e.id:
+		e.renew(now)
+		if e.leader {
+			return false
+		}
+		e.leader = true
+		return true
+
+	case lease.ID == "" || lease.ExpiresAt.Before(now):
+		e.leader = false
+		e.renew(now)
+		return false
+
+	default:
+		e.leader = false
+		return false
+	}
+}
+
+func (e *leaderElection) renew(now time.Time) {
+	if err := e.storage.Set(e.key, leaderLease{
+		ID:        e.id,
+		ExpiresAt: now.Add(leaderTTL),
+	}); err != nil {
+		Log(errors.New("writing leader lease failed").
+			WithTag("key", e.key).
+			Wrap(err))
+	}
+}
+
+func (e *leaderElection) resign() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var lease leaderLease
+	e.storage.Get(e.key, &lease)
+	if lease.ID == e.id {
+		e.storage.Del(e.key)
+	}
+	e.leader = false
+	e.cancelLeadership()
+}
@@ -0,0 +1,165 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLeadership(t *testing.T) {
+	testSkipWasm(t)
+
+	ctx := makeTestContext()
+	called := false
+	ctx.RequestLeadership("test", func(ctx Context) {
+		called = true
+	})
+	require.False(t, called)
+}
+
+func TestRequestLeadershipLock(t *testing.T) {
+	testSkipNonWasm(t)
+
+	locks := Window().Get("navigator").Get("locks")
+	if !locks.Truthy() {
+		t.Skip("web locks not supported")
+	}
+
+	var m nodeManager
+	ctx := makeTestContext()
+	div, err := m.Mount(ctx, 1, Div())
+	require.NoError(t, err)
+	ctx = m.context(ctx, div)
+
+	leaderCtxs := make(chan Context, 1)
+	requestLeadershipLock(ctx, locks, "test-lock", func(ctx Context) {
+		leaderCtxs <- ctx
+	})
+	leaderCtx := <-leaderCtxs
+	require.NoError(t, leaderCtx.Err())
+
+	steal := FuncOf(func(this Value, args []Value) any {
+		return nil
+	})
+	defer steal.Release()
+	locks.Call("request", leaderLockPrefix+"test-lock", map[string]any{"steal": true}, steal)
+
+	select {
+	case <-leaderCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("leadership context is not cancelled when the lock is stolen")
+	}
+	require.NoError(t, ctx.Err())
+}
+
+func TestLeaderElection(t *testing.T) {
+	storage := NewMemoryStorage()
+	now := time.Now()
+
+	a := &leaderElection{
+		key:     leaderStorageKeyPrefix + "test",
+		id:      "a",
+		storage: storage,
+	}
+	b := &leaderElection{
+		key:     leaderStorageKeyPrefix + "test",
+		id:      "b",
+		storage: storage,
+	}
+
+	t.Run("first claim does not elect", func(t *testing.T) {
+		require.False(t, a.tick(now))
+		require.False(t, a.leader)
+	})
+
+	t.Run("claim is not taken over while valid", func(t *testing.T) {
+		require.False(t, b.tick(now))
+		require.False(t, b.leader)
+	})
+
+	t.Run("confirmed claim elects", func(t *testing.T) {
+		now = now.Add(leaderHeartbeat)
+		require.True(t, a.tick(now))
+		require.True(t, a.leader)
+	})
+
+	t.Run("leadership is reported once", func(t *testing.T) {
+		now = now.Add(leaderHeartbeat)
+		require.False(t, a.tick(now))
+		require.True(t, a.leader)
+	})
+
+	t.Run("expired lease is taken over", func(t *testing.T) {
+		now = now.Add(leaderTTL * 2)
+		require.False(t, b.tick(now))
+
+		now = now.Add(leaderHeartbeat)
+		require.True(t, b.tick(now))
+		require.False(t, a.tick(now))
+		require.False(t, a.leader)
+	})
+
+	t.Run("resign releases the lease", func(t *testing.T) {
+		a.resign()
+		require.True(t, storage.Contains(b.key))
+
+		b.resign()
+		require.False(t, storage.Contains(b.key))
+		require.False(t, b.leader)
+	})
+}
+
+func TestLeaderElectionStep(t *testing.T) {
+	var m nodeManager
+	ctx := makeTestContext()
+	div, err := m.Mount(ctx, 1, Div())
+	require.NoError(t, err)
+	ctx = m.context(ctx, div)
+
+	storage := NewMemoryStorage()
+	now := time.Now()
+
+	a := &leaderElection{
+		key:     leaderStorageKeyPrefix + "test",
+		id:      "a",
+		storage: storage,
+	}
+	b := &leaderElection{
+		key:     leaderStorageKeyPrefix + "test",
+		id:      "b",
+		storage: storage,
+	}
+
+	var leaderCtx Context
+	lead := func(ctx Context) {
+		leaderCtx = ctx
+	}
+
+	a.step(ctx, lead, now)
+	require.Nil(t, leaderCtx.Context)
+
+	now = now.Add(leaderHeartbeat)
+	a.step(ctx, lead, now)
+	require.NotNil(t, leaderCtx.Context)
+	require.NoError(t, leaderCtx.Err())
+
+	t.Run("leadership context is cancelled when leadership is lost", func(t *testing.T) {
+		now = now.Add(leaderTTL * 2)
+		b.step(ctx, lead, now)
+		a.step(ctx, lead, now)
+		require.False(t, a.leader)
+		require.Error(t, leaderCtx.Err())
+		require.NoError(t, ctx.Err())
+	})
+
+	t.Run("leadership context is cancelled on resign", func(t *testing.T) {
+		now = now.Add(leaderHeartbeat)
+		b.step(ctx, lead, now)
+		require.True(t, b.leader)
+		require.NoError(t, leaderCtx.Err())
+
+		b.resign()
+		require.Error(t, leaderCtx.Err())
+	})
+}
@@ -43,9 +43,16 @@ func Log(v ...any) {
 	Logf(b.String(), v...)
 }
 
-// Logf logs according to a format specifier.
+// Logf logs according to a format specifier. Logged errors are passed to the
+// function set with SetErrorReporter.
 func Logf(format string, v ...any) {
 	DefaultLogger(format, v...)
+
+	for _, a := range v {
+		if err, ok := a.(error); ok {
+			reportError(ErrorReport{Err: err})
+		}
+	}
 }
 
 func serverLog(format string, v ...any) {
@@ -0,0 +1,274 @@
+package app
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// The viewport width used during pre-rendering when the request only hints
+	// that it comes from a desktop browser.
+	defaultDesktopViewportWidth = 1280
+
+	// The client hints requested by pages in order to pre-render them for the
+	// client viewport.
+	viewportClientHints = "Sec-CH-Viewport-Width, Viewport-Width, Sec-CH-Viewport-Height"
+)
+
+var (
+	breakpoints = []Breakpoint{
+		{Name: "xs", MinWidth: 0},
+		{Name: "sm", MinWidth: 480},
+		{Name: "md", MinWidth: 768},
+		{Name: "lg", MinWidth: 1024},
+		{Name: "xl", MinWidth: 1440},
+	}
+)
+
+// Breakpoint represents a named viewport width range that starts at MinWidth
+// and ends at the MinWidth of the next breakpoint.
+type Breakpoint struct {
+	// The name reported by Context.Breakpoint.
+	Name string
+
+	// The minimum viewport width in px.
+	MinWidth int
+}
+
+// SetBreakpoints replaces the breakpoints reported by Context.Breakpoint.
+// Default breakpoints are xs (0px), sm (480px), md (768px), lg (1024px) and xl
+// (1440px).
+//
+// It should be called before the app is started, both on the server and the
+// client.
+func SetBreakpoints(v ...Breakpoint) {
+	v = slices.Clone(v)
+	slices.SortStableFunc(v, func(a, b Breakpoint) int {
+		return a.MinWidth - b.MinWidth
+	})
+	breakpoints = v
+}
+
+// breakpoint returns the name of the widest breakpoint whose media query
+// matches.
+func breakpoint(match func(query string) bool) string {
+	var name string
+	for _, b := range breakpoints {
+		if b.MinWidth > 0 && !match(minWidthQuery(b.MinWidth)) {
+			break
+		}
+		name = b.Name
+	}
+	return name
+}
+
+func minWidthQuery(px int) string {
+	return "(min-width: " + strconv.Itoa(px) + "px)"
+}
+
+// mediaManager manages the media queries observed by components. Queries are
+// evaluated with matchMedia on the client, and components are updated only when
+// the result of a query they use changes. Observers that are no longer mounted
+// are removed during cleanup.
+type mediaManager struct {
+	mutex   sync.Mutex
+	queries map[string]*mediaQuery
+}
+
+type mediaQuery struct {
+	list      Value
+	onChange  Func
+	matches   bool
+	observers map[UI]Context
+}
+
+// Match reports whether the given media query matches and registers the
+// component enclosing the context source as an observer of the query.
+//
+// On the server, the query is evaluated against the page size, which is set
+// from the request hints.
+func (m *mediaManager) Match(ctx Context, query string) bool {
+	if IsServer {
+		width, height := ctx.Page().Size()
+		return matchMediaSize(query, width, height)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	q, ok := m.queries[query]
+	if !ok {
+		q = m.listen(ctx, query)
+	}
+
+	if c, ok := component(ctx.sourceElement); ok {
+		ctx.sourceElement = c
+		q.observers[c] = ctx
+	}
+	return q.matches
+}
+
+func (m *mediaManager) listen(ctx Context, query string) *mediaQuery {
+	q := &mediaQuery{
+		list:      Window().Call("matchMedia", query),
+		observers: make(map[UI]Context),
+	}
+	q.matches = q.list.Get("matches").Bool()
+	q.onChange = FuncOf(func(this Value, args []Value) any {
+		m.change(query, args[0].Get("matches").Bool())
+		return nil
+	})
+	q.list.Call("addEventListener", "change", q.onChange)
+
+	if m.queries == nil {
+		m.queries = make(map[string]*mediaQuery)
+	}
+	m.queries[query] = q
+	return q
+}
+
+// change records the new result of the given query and updates its
+// observers.
+func (m *mediaManager) change(query string, matches bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	q, ok := m.queries[query]
+	if !ok || q.matches == matches {
+		return
+	}
+	q.matches = matches
+
+	for source, ctx := range q.observers {
+		if !source.Mounted() {
+			delete(q.observers, source)
+			continue
+		}
+		ctx.Dispatch(nil)
+	}
+}
+
+// Cleanup removes the observers that are no longer mounted.
+func (m *mediaManager) Cleanup() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, q := range m.queries {
+		for source := range q.observers {
+			if !source.Mounted() {
+				delete(q.observers, source)
+			}
+		}
+	}
+}
+
+// matchMediaSize reports whether the given media query matches a viewport of
+// the given size. Only the screen and all media types and the width and height
+// features are supported, other queries never match.
+func matchMediaSize(query string, width, height int) bool {
+	for _, q := range strings.Split(query, ",") {
+		if matchMediaSizeQuery(q, width, height) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchMediaSizeQuery(query string, width, height int) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	query = strings.TrimPrefix(query, "only ")
+	if query == "" {
+		return false
+	}
+
+	for _, cond := range strings.Split(query, " and ") {
+		cond = strings.TrimSpace(cond)
+		switch cond {
+		case "all", "screen":
+			continue
+		}
+
+		if !strings.HasPrefix(cond, "(") || !strings.HasSuffix(cond, ")") {
+			return false
+		}
+
+		feature, value, ok := strings.Cut(cond[1:len(cond)-1], ":")
+		if !ok {
+			return false
+		}
+
+		px, ok := parseMediaLength(value)
+		if !ok {
+			return false
+		}
+
+		switch strings.TrimSpace(feature) {
+		case "min-width":
+			ok = width >= px
+		case "max-width":
+			ok = width <= px
+		case "min-height":
+			ok = height >= px
+		case "max-height":
+			ok = height <= px
+		default:
+			ok = false
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// parseMediaLength returns the given px, em or rem length in px, em and rem
+// being 16px.
+func parseMediaLength(v string) (int, bool) {
+	v = strings.TrimSpace(v)
+
+	unit := 1.0
+	switch {
+	case strings.HasSuffix(v, "px"):
+		v = strings.TrimSuffix(v, "px")
+	case strings.HasSuffix(v, "rem"):
+		v = strings.TrimSuffix(v, "rem")
+		unit = 16
+	case strings.HasSuffix(v, "em"):
+		v = strings.TrimSuffix(v, "em")
+		unit = 16
+	case v != "0":
+		return 0, false
+	}
+
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		return 0, false
+	}
+	return int(f * unit), true
+}
+
+// viewportHints returns the viewport size hinted by the given request. The
+// width is read from the Sec-CH-Viewport-Width header, or from the legacy
+// Viewport-Width one. When there is none, the width of a desktop viewport is
+// returned for requests hinted as not coming from a mobile device, and 0
+// otherwise.
+func viewportHints(r *http.Request) (width, height int) {
+	headerInt := func(names ...string) int {
+		for _, name := range names {
+			if v, err := strconv.Atoi(strings.TrimSpace(r.Header.Get(name))); err == nil && v > 0 {
+				return v
+			}
+		}
+		return 0
+	}
+
+	width = headerInt("Sec-CH-Viewport-Width", "Viewport-Width")
+	height = headerInt("Sec-CH-Viewport-Height")
+	if width == 0 && r.Header.Get("Sec-CH-UA-Mobile") == "?0" {
+		width = defaultDesktopViewportWidth
+	}
+	return width, height
+}
@@ -0,0 +1,236 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/media-test", func() Composer { return &mediaTestCompo{} })
+}
+
+type mediaTestCompo struct {
+	Compo
+
+	breakpoint string
+}
+
+func (c *mediaTestCompo) OnPreRender(ctx Context) {
+	c.breakpoint = ctx.Breakpoint()
+}
+
+func (c *mediaTestCompo) Render() UI {
+	return Div().
+		ID("media-test").
+		DataSet("breakpoint", c.breakpoint)
+}
+
+func TestSetBreakpoints(t *testing.T) {
+	defaultBreakpoints := breakpoints
+	defer func() {
+		breakpoints = defaultBreakpoints
+	}()
+
+	SetBreakpoints(
+		Breakpoint{Name: "desktop", MinWidth: 1024},
+		Breakpoint{Name: "mobile"},
+		Breakpoint{Name: "tablet", MinWidth: 600},
+	)
+	require.Equal(t, []Breakpoint{
+		{Name: "mobile"},
+		{Name: "tablet", MinWidth: 600},
+		{Name: "desktop", MinWidth: 1024},
+	}, breakpoints)
+}
+
+func TestBreakpoint(t *testing.T) {
+	utests := []struct {
+		width    int
+		expected string
+	}{
+		{width: 0, expected: "xs"},
+		{width: 479, expected: "xs"},
+		{width: 480, expected: "sm"},
+		{width: 800, expected: "md"},
+		{width: 1024, expected: "lg"},
+		{width: 2560, expected: "xl"},
+	}
+
+	for _, u := range utests {
+		t.Run(u.expected, func(t *testing.T) {
+			var queries []string
+			res := breakpoint(func(query string) bool {
+				queries = append(queries, query)
+				return matchMediaSize(query, u.width, 0)
+			})
+			require.Equal(t, u.expected, res)
+			require.NotEmpty(t, queries)
+		})
+	}
+}
+
+func TestMatchMediaSize(t *testing.T) {
+	utests := []struct {
+		query    string
+		width    int
+		height   int
+		expected bool
+	}{
+		{query: "(min-width: 768px)", width: 1024, expected: true},
+		{query: "(min-width: 768px)", width: 320},
+		{query: "(max-width: 480px)", width: 320, expected: true},
+		{query: "(max-width: 30em)", width: 320, expected: true},
+		{query: "(max-width: 30rem)", width: 500},
+		{query: "screen and (min-width: 768px) and (max-width: 1023px)", width: 800, expected: true},
+		{query: "only screen and (min-width: 768px) and (max-width: 1023px)", width: 1024},
+		{query: "(min-height: 600px)", height: 800, expected: true},
+		{query: "(max-height: 600px)", height: 800},
+		{query: "(max-width: 480px), (min-width: 1024px)", width: 1280, expected: true},
+		{query: "print and (min-width: 0px)", width: 1280},
+		{query: "(prefers-color-scheme: dark)", width: 1280},
+		{query: "(min-width: 50%)", width: 1280},
+		{query: "min-width: 768px", width: 1280},
+		{query: "", width: 1280},
+	}
+
+	for _, u := range utests {
+		t.Run(u.query, func(t *testing.T) {
+			require.Equal(t, u.expected, matchMediaSize(u.query, u.width, u.height))
+		})
+	}
+}
+
+func TestViewportHints(t *testing.T) {
+	utests := []struct {
+		scenario       string
+		headers        map[string]string
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{
+			scenario: "no hints",
+		},
+		{
+			scenario: "viewport width",
+			headers: map[string]string{
+				"Sec-CH-Viewport-Width":  "820",
+				"Sec-CH-Viewport-Height": "1180",
+			},
+			expectedWidth:  820,
+			expectedHeight: 1180,
+		},
+		{
+			scenario: "legacy viewport width",
+			headers: map[string]string{
+				"Viewport-Width": "390",
+			},
+			expectedWidth: 390,
+		},
+		{
+			scenario: "desktop",
+			headers: map[string]string{
+				"Sec-CH-UA-Mobile": "?0",
+			},
+			expectedWidth: defaultDesktopViewportWidth,
+		},
+		{
+			scenario: "mobile",
+			headers: map[string]string{
+				"Sec-CH-UA-Mobile": "?1",
+			},
+		},
+		{
+			scenario: "invalid viewport width",
+			headers: map[string]string{
+				"Sec-CH-Viewport-Width": "wide",
+			},
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range u.headers {
+				r.Header.Set(k, v)
+			}
+
+			width, height := viewportHints(r)
+			require.Equal(t, u.expectedWidth, width)
+			require.Equal(t, u.expectedHeight, height)
+		})
+	}
+}
+
+func TestMediaManagerMatch(t *testing.T) {
+	testSkipWasm(t)
+
+	var m mediaManager
+	ctx := makeTestContext()
+	origin, _ := url.Parse("https://goapp.dev")
+	page := makeRequestPage(origin, ctx.resolveURL)
+	page.width = 800
+	ctx.page = func() Page { return &page }
+	ctx.matchMedia = m.Match
+
+	require.True(t, ctx.MatchMedia("(min-width: 768px)"))
+	require.False(t, ctx.MatchMedia("(min-width: 1024px)"))
+	require.Equal(t, "md", ctx.Breakpoint())
+	require.Empty(t, m.queries)
+}
+
+func TestMediaManagerChange(t *testing.T) {
+	var nm nodeManager
+	var updates []Composer
+
+	ctx := makeTestContext()
+	ctx.addComponentUpdate = func(c Composer, n int) {
+		updates = append(updates, c)
+	}
+
+	compo, err := nm.Mount(ctx, 1, &hello{})
+	require.NoError(t, err)
+	observerCtx := nm.context(ctx, compo)
+	updates = nil
+
+	m := mediaManager{
+		queries: map[string]*mediaQuery{
+			"(min-width: 768px)": {
+				observers: map[UI]Context{compo: observerCtx},
+			},
+		},
+	}
+
+	m.change("(min-width: 768px)", false)
+	require.Empty(t, updates)
+
+	m.change("(min-width: 768px)", true)
+	require.Equal(t, []Composer{compo.(Composer)}, updates)
+	require.True(t, m.queries["(min-width: 768px)"].matches)
+
+	m.change("(max-width: 480px)", true)
+	require.Len(t, updates, 1)
+
+	nm.Dismount(compo)
+	m.Cleanup()
+	require.Empty(t, m.queries["(min-width: 768px)"].observers)
+}
+
+func TestHandlerServePageWithViewportHints(t *testing.T) {
+	testSkipWasm(t)
+
+	r := httptest.NewRequest(http.MethodGet, "/media-test", nil)
+	r.Header.Set("Sec-CH-Viewport-Width", "1100")
+	w := httptest.NewRecorder()
+
+	h := Handler{}
+	h.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Header().Get("Accept-CH"), "Sec-CH-Viewport-Width")
+	require.Equal(t, "Sec-CH-Viewport-Width, Viewport-Width, Sec-CH-Viewport-Height, Sec-CH-UA-Mobile", w.Header().Get("Vary"))
+	require.Contains(t, w.Body.String(), `data-breakpoint="lg"`)
+}
@@ -2,6 +2,8 @@ package app
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"html"
 	"io"
 	"reflect"
@@ -118,6 +120,9 @@ func (m nodeManager) Mount(ctx Context, depth uint, v UI) (UI, error) {
 	case *raw:
 		return m.mountRawHTML(depth, v)
 
+	case *portal:
+		return m.mountPortal(ctx, depth, v)
+
 	default:
 		return nil, errors.New("unsupported element").
 			WithTag("type", reflect.TypeOf(v)).
@@ -224,9 +229,16 @@ func (m nodeManager) mountComponent(ctx Context, depth uint, v Composer) (UI, er
 
 	v = v.setRef(v)
 	v = v.setDepth(depth)
+	v = v.setLifecycle(context.WithCancel(ctx.Context))
+	ctx.Context = v.lifecycle()
 
-	if initializer, ok := v.(Initializer); ok {
-		initializer.OnInit()
+	if err := m.initComponent(v); err != nil {
+		v.setRef(nil)
+		v.endLifecycle()
+		return nil, errors.New("initializing component failed").
+			WithTag("type", reflect.TypeOf(v)).
+			WithTag("depth", v.depth()).
+			Wrap(err)
 	}
 
 	if preRenderer, ok := v.(PreRenderer); ok && IsServer {
@@ -237,18 +249,34 @@ func (m nodeManager) mountComponent(ctx Context, depth uint, v Composer) (UI, er
 		ctx.Dispatch(mounter.OnMount)
 	}
 
-	root, err := m.renderComponent(v)
+	rendering, err := m.renderComponent(v)
 	if err != nil {
 		return nil, errors.New("rendering component failed").
 			WithTag("type", reflect.TypeOf(v)).
 			WithTag("depth", v.depth()).
 			Wrap(err)
 	}
-	if root, err = m.Mount(ctx, depth+1, root); err != nil {
-		return nil, errors.New("mounting component root failed").
+	root, err := m.Mount(ctx, depth+1, rendering)
+	if err != nil {
+		// The elements mounted before the failure are dismounted to release
+		// their resources.
+		m.Dismount(rendering)
+
+		err = errors.New("mounting component root failed").
 			WithTag("type", reflect.TypeOf(v)).
 			WithTag("depth", v.depth()).
 			Wrap(err)
+
+		boundary, ok := v.(ErrorBoundary)
+		if !ok {
+			v.setRef(nil)
+			v.endLifecycle()
+			return nil, err
+		}
+		if err = m.recoverErrorBoundary(ctx, boundary, nil, err); err != nil {
+			return nil, err
+		}
+		return v, nil
 	}
 	root = root.setParent(v)
 	v = v.setRoot(root)
@@ -256,10 +284,35 @@ func (m nodeManager) mountComponent(ctx Context, depth uint, v Composer) (UI, er
 	return v, nil
 }
 
-func (m nodeManager) renderComponent(v Composer) (UI, error) {
+func (m nodeManager) initComponent(v Composer) (err error) {
+	initializer, ok := v.(Initializer)
+	if !ok {
+		return nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = componentFailure(v, panicError(r))
+		}
+	}()
+
+	initializer.OnInit()
+	return nil
+}
+
+func (m nodeManager) renderComponent(v Composer) (root UI, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = componentFailure(v, panicError(r))
+		}
+	}()
+
 	rendering := FilterUIElems(v.Render())
 	if len(rendering) == 0 {
-		return nil, errors.New("render method does not returns a text, html element, or component")
+		return nil, componentFailure(v, errors.New("render method does not returns a text, html element, or component"))
+	}
+	if err := addStyleScope(v, rendering[0]); err != nil {
+		return nil, componentFailure(v, errors.New("adding style scope failed").Wrap(err))
 	}
 	return rendering[0], nil
 }
@@ -280,8 +333,58 @@ func (m nodeManager) mountRawHTML(depth uint, v *raw) (UI, error) {
 	return v, nil
 }
 
-// Dismount removes a UI element based on its type.
+func (m nodeManager) mountPortal(ctx Context, depth uint, v *portal) (UI, error) {
+	if v.Mounted() {
+		return nil, errors.New("portal is already mounted").
+			WithTag("parent-type", reflect.TypeOf(v.parent())).
+			WithTag("target", v.target).
+			WithTag("depth", v.depth())
+	}
+
+	target := v.targetElement()
+	if IsClient && !target.Truthy() {
+		return nil, errors.New("portal target not found").
+			WithTag("target", v.target).
+			WithTag("depth", depth)
+	}
+
+	v.jsElement, _ = Window().createElement("template", "")
+	v.container, _ = Window().createElement("div", "")
+	setJSAttribute(v.container, "style", "display:contents")
+
+	v.treeDepth = depth
+	for i, child := range v.children {
+		var err error
+		if child, err = m.Mount(ctx, depth+1, child); err != nil {
+			for _, c := range v.children[:i+1] {
+				m.Dismount(c)
+			}
+			v.container = nil
+			v.jsElement = nil
+
+			return nil, errors.New("mounting child failed").
+				WithTag("type", reflect.TypeOf(v)).
+				WithTag("target", v.target).
+				WithTag("depth", depth).
+				WithTag("index", i).
+				Wrap(err)
+		}
+		child = child.setParent(v)
+		v.children[i] = child
+		v.container.appendChild(child)
+	}
+
+	target.appendChild(v.container)
+	return v, nil
+}
+
+// Dismount removes a UI element based on its type. Elements that are not
+// mounted are ignored.
 func (m nodeManager) Dismount(v UI) {
+	if v == nil || !v.Mounted() {
+		return
+	}
+
 	switch v := v.(type) {
 	case *text:
 
@@ -293,6 +396,9 @@ func (m nodeManager) Dismount(v UI) {
 
 	case *raw:
 		m.dismountRawHTML(v)
+
+	case *portal:
+		m.dismountPortal(v)
 	}
 }
 
@@ -317,6 +423,7 @@ func (m nodeManager) dismountHTMLEventHandler(handler eventHandler) {
 func (m nodeManager) dismountComponent(v Composer) {
 	m.Dismount(v.root())
 	v.setRef(nil)
+	v.endLifecycle()
 
 	if dismounter, ok := v.(Dismounter); ok {
 		dismounter.OnDismount()
@@ -327,6 +434,16 @@ func (m nodeManager) dismountRawHTML(v *raw) {
 	v.jsElement = nil
 }
 
+func (m nodeManager) dismountPortal(v *portal) {
+	for _, child := range v.children {
+		m.Dismount(child)
+	}
+
+	v.container.Call("remove")
+	v.container = nil
+	v.jsElement = nil
+}
+
 // CanUpdate determines whether a given UI element 'v' can be updated with a new
 // UI element 'new'. It returns false if the types of the two elements are
 // different.
@@ -345,6 +462,9 @@ func (m nodeManager) CanUpdate(v, new UI) bool {
 	case *htmlElem, *htmlElemSelfClosing:
 		return v.(HTML).Tag() == new.(HTML).Tag()
 
+	case *portal:
+		return v.(*portal).target == new.(*portal).target
+
 	default:
 		return true
 	}
@@ -372,6 +492,9 @@ func (m nodeManager) Update(ctx Context, v, new UI) (UI, error) {
 	case *raw:
 		return m.updateRawHTML(ctx, v, new.(*raw))
 
+	case *portal:
+		return m.updatePortal(ctx, v, new.(*portal))
+
 	default:
 		return nil, errors.New("unsupported element").WithTag("type", reflect.TypeOf(v))
 	}
@@ -406,19 +529,37 @@ func (m nodeManager) updateHTML(ctx Context, v, new HTML) (UI, error) {
 		m.updateHTMLEventHandlers(ctx, v, newEvents)
 	}
 
-	children := v.body()
-	newChildren := new.body()
+	children, err := m.updateChildren(ctx, v, v.JSValue(), v.depth(), v.body(), new.body())
+	if err != nil {
+		return nil, errors.New("updating html element failed").
+			WithTag("type", reflect.TypeOf(v)).
+			WithTag("tag", v.Tag()).
+			WithTag("depth", v.depth()).
+			Wrap(err)
+	}
+
+	v = v.setBody(children)
+	return v, nil
+}
+
+// updateChildren updates the children of the given element with the new ones.
+// Children that cannot be updated are replaced by newly mounted elements within
+// the given javascript container. It returns the updated children.
+func (m nodeManager) updateChildren(ctx Context, v UI, container Value, depth uint, children, newChildren []UI) ([]UI, error) {
+	transition, animated := transitionOf(v)
+	var positions map[string]transitionPosition
+	if animated {
+		positions = transition.positions(children)
+	}
+
 	sharedLen := min(len(children), len(newChildren))
-	for i := 0; i < min(len(children), len(newChildren)); i++ {
+	for i := 0; i < sharedLen; i++ {
 		child := children[i]
 		newChild := newChildren[i]
 		if m.CanUpdate(child, newChild) {
 			child, err := m.Update(ctx, child, newChild)
 			if err != nil {
 				return nil, errors.New("updating child failed").
-					WithTag("type", reflect.TypeOf(v)).
-					WithTag("tag", v.Tag()).
-					WithTag("depth", v.depth()).
 					WithTag("index", i).
 					Wrap(err)
 			}
@@ -426,16 +567,19 @@ func (m nodeManager) updateHTML(ctx Context, v, new HTML) (UI, error) {
 			continue
 		}
 
-		newChild, err := m.Mount(ctx, v.depth()+1, newChildren[i])
+		newChild, err := m.Mount(ctx, depth+1, newChildren[i])
 		if err != nil {
 			return nil, errors.New("mounting child failed").
-				WithTag("type", reflect.TypeOf(v)).
-				WithTag("tag", v.Tag()).
-				WithTag("depth", v.depth()).
 				WithTag("index", i).
 				Wrap(err)
 		}
-		v.JSValue().replaceChild(newChild, child)
+		if animated {
+			container.Call("insertBefore", newChild, child)
+			transition.enter(newChild)
+			transition.leave(child)
+		} else {
+			container.replaceChild(newChild, child)
+		}
 		newChild = newChild.setParent(v)
 		children[i] = newChild
 		m.Dismount(child)
@@ -443,29 +587,35 @@ func (m nodeManager) updateHTML(ctx Context, v, new HTML) (UI, error) {
 
 	for i := sharedLen; i < len(children); i++ {
 		child := children[i]
-		v.JSValue().removeChild(child)
+		if animated {
+			transition.leave(child)
+		} else {
+			container.removeChild(child)
+		}
 		m.Dismount(child)
 		children[i] = nil
 	}
 	children = children[:sharedLen]
 
 	for i := sharedLen; i < len(newChildren); i++ {
-		newChild, err := m.Mount(ctx, v.depth()+1, newChildren[i])
+		newChild, err := m.Mount(ctx, depth+1, newChildren[i])
 		if err != nil {
 			return nil, errors.New("mounting child failed").
-				WithTag("type", reflect.TypeOf(v)).
-				WithTag("tag", v.Tag()).
-				WithTag("depth", v.depth()).
 				WithTag("index", i).
 				Wrap(err)
 		}
-		v.JSValue().appendChild(newChild)
+		container.appendChild(newChild)
+		if animated {
+			transition.enter(newChild)
+		}
 		newChild = newChild.setParent(v)
 		children = append(children, newChild)
 	}
 
-	v = v.setBody(children)
-	return v, nil
+	if animated {
+		transition.move(children, positions)
+	}
+	return children, nil
 }
 
 func (m nodeManager) updateHTMLAttributes(ctx Context, v HTML, newAttrs attributes) {
@@ -540,14 +690,33 @@ func (m nodeManager) updateComponent(ctx Context, v, new Composer) (UI, error) {
 		return v, nil
 	}
 
-	if updater, ok := v.(Updater); ok {
-		updater.OnUpdate(ctx)
+	if err := m.notifyComponentUpdate(ctx, v); err != nil {
+		return nil, errors.New("updating component failed").
+			WithTag("type", reflect.TypeOf(v)).
+			WithTag("depth", v.depth()).
+			Wrap(err)
 	}
 
 	ctx.removeComponentUpdate(v)
 	return m.UpdateComponentRoot(ctx, v)
 }
 
+func (m nodeManager) notifyComponentUpdate(ctx Context, v Composer) (err error) {
+	updater, ok := v.(Updater)
+	if !ok {
+		return nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = componentFailure(v, panicError(r))
+		}
+	}()
+
+	updater.OnUpdate(ctx)
+	return nil
+}
+
 // UpdateComponentRoot updates the root element of the given component.
 func (m nodeManager) UpdateComponentRoot(ctx Context, v Composer) (UI, error) {
 	ctx = m.context(ctx, v)
@@ -561,34 +730,153 @@ func (m nodeManager) UpdateComponentRoot(ctx Context, v Composer) (UI, error) {
 			Wrap(err)
 	}
 
+	if err := m.updateComponentRoot(ctx, v, root, newRoot); err != nil {
+		boundary, ok := v.(ErrorBoundary)
+		if !ok {
+			return nil, err
+		}
+		if err = m.recoverErrorBoundary(ctx, boundary, v.root(), err); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+func (m nodeManager) updateComponentRoot(ctx Context, v Composer, root, newRoot UI) error {
 	if m.CanUpdate(root, newRoot) {
-		if root, err = m.Update(ctx, root, newRoot); err != nil {
-			return nil, errors.New("updating component root failed").
+		root, err := m.Update(ctx, root, newRoot)
+		if err != nil {
+			return errors.New("updating component root failed").
 				WithTag("type", reflect.TypeOf(v)).
 				WithTag("depth", v.depth()).
 				Wrap(err)
 		}
 		v.setRoot(root)
-	} else {
-		if newRoot, err = m.Mount(ctx, v.depth()+1, newRoot); err != nil {
-			return nil, errors.New("mounting component root failed").
-				WithTag("type", reflect.TypeOf(v)).
-				WithTag("depth", v.depth()).
-				Wrap(err)
-		}
+		return nil
+	}
 
-		for parent := v.parent(); parent != nil; parent = parent.parent() {
-			if parent, isHTML := parent.(HTML); isHTML {
-				parent.JSValue().replaceChild(newRoot, root)
-				break
-			}
+	newRoot, err := m.Mount(ctx, v.depth()+1, newRoot)
+	if err != nil {
+		return errors.New("mounting component root failed").
+			WithTag("type", reflect.TypeOf(v)).
+			WithTag("depth", v.depth()).
+			Wrap(err)
+	}
+	m.replaceComponentRoot(v, root, newRoot)
+	return nil
+}
+
+func (m nodeManager) replaceComponentRoot(v Composer, root, newRoot UI) {
+	for parent := v.parent(); parent != nil; parent = parent.parent() {
+		if container, ok := containerElement(parent); ok {
+			container.replaceChild(newRoot, root)
+			break
 		}
-		newRoot.setParent(v)
+	}
+	newRoot.setParent(v)
+	v.setRoot(newRoot)
+	m.Dismount(root)
+}
+
+// recoverErrorBoundary reports the given error to the error boundary, then
+// replaces its root with a freshly mounted rendering. The root is nil when the
+// error boundary is being mounted.
+func (m nodeManager) recoverErrorBoundary(ctx Context, v ErrorBoundary, root UI, err error) error {
+	ctx = m.context(ctx, v)
+	reportComponentError(v, err)
+	v.OnError(ctx, err)
+
+	newRoot, err := m.renderComponent(v)
+	if err != nil {
+		return errors.New("rendering error boundary failed").
+			WithTag("type", reflect.TypeOf(v)).
+			WithTag("depth", v.depth()).
+			Wrap(err)
+	}
+	if newRoot, err = m.Mount(ctx, v.depth()+1, newRoot); err != nil {
+		return errors.New("mounting error boundary root failed").
+			WithTag("type", reflect.TypeOf(v)).
+			WithTag("depth", v.depth()).
+			Wrap(err)
+	}
+
+	if root == nil {
+		newRoot = newRoot.setParent(v)
 		v.setRoot(newRoot)
-		m.Dismount(root)
+		return nil
 	}
+	m.replaceComponentRoot(v, root, newRoot)
+	return nil
+}
 
-	return v, nil
+// CatchError passes the given error to the closest error boundary enclosing
+// the given element, which replaces its content with a fallback. The error is
+// passed to the next enclosing error boundary when the fallback fails. It
+// returns an error when no error boundary recovered from the failure.
+func (m nodeManager) CatchError(ctx Context, v UI, err error) error {
+	for boundary, ok := errorBoundary(v); ok; boundary, ok = errorBoundary(boundary) {
+		if err = m.recoverErrorBoundary(ctx, boundary, boundary.root(), err); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// CatchPanic passes the given recovered panic to the closest error boundary
+// enclosing the given context source and schedules its update. It reports
+// whether an error boundary caught the panic.
+func (m nodeManager) CatchPanic(ctx Context, r any) bool {
+	boundary, ok := errorBoundary(ctx.sourceElement)
+	if !ok {
+		return false
+	}
+
+	err := componentFailure(ctx.sourceElement, panicError(r))
+	reportComponentError(ctx.sourceElement, err)
+
+	ctx = m.context(ctx, boundary)
+	boundary.OnError(ctx, err)
+	ctx.Update()
+	return true
+}
+
+// errorBoundary returns the closest mounted error boundary among the ancestors
+// of the component that contains the given element.
+func errorBoundary(v UI) (ErrorBoundary, bool) {
+	c, ok := component(v)
+	if !ok {
+		return nil, false
+	}
+
+	for parent := c.parent(); parent != nil; parent = parent.parent() {
+		if boundary, ok := parent.(ErrorBoundary); ok && boundary.Mounted() {
+			return boundary, true
+		}
+	}
+	return nil, false
+}
+
+func panicError(r any) errors.Error {
+	err := errors.New("panic recovered").
+		WithTag("panic", r).
+		WithTag(panickedErrorTag, true)
+	if e, ok := r.(error); ok {
+		err = err.Wrap(e)
+	}
+	return err
+}
+
+// componentFailure tags the given error with the type and depth of the
+// component that contains the given element, in order to report where the
+// failure occurred once it is caught by an error boundary.
+func componentFailure(v UI, err errors.Error) errors.Error {
+	c, ok := component(v)
+	if !ok {
+		return err
+	}
+	return err.
+		WithTag(componentTypeErrorTag, fmt.Sprintf("%T", c)).
+		WithTag(componentDepthErrorTag, c.depth())
 }
 
 func (m nodeManager) updateRawHTML(ctx Context, v, new *raw) (UI, error) {
@@ -606,8 +894,8 @@ func (m nodeManager) updateRawHTML(ctx Context, v, new *raw) (UI, error) {
 	}
 
 	for parent := v.parent(); parent != nil; parent = parent.parent() {
-		if parent, isHTML := parent.(HTML); isHTML {
-			parent.JSValue().replaceChild(newMount, v)
+		if container, ok := containerElement(parent); ok {
+			container.replaceChild(newMount, v)
 			newMount.setParent(parent)
 			break
 		}
@@ -616,9 +904,25 @@ func (m nodeManager) updateRawHTML(ctx Context, v, new *raw) (UI, error) {
 	return newMount, nil
 }
 
+func (m nodeManager) updatePortal(ctx Context, v, new *portal) (UI, error) {
+	children, err := m.updateChildren(ctx, v, v.container, v.depth(), v.children, new.children)
+	if err != nil {
+		return nil, errors.New("updating portal failed").
+			WithTag("type", reflect.TypeOf(v)).
+			WithTag("target", v.target).
+			WithTag("depth", v.depth()).
+			Wrap(err)
+	}
+	v.children = children
+	return v, nil
+}
+
 func (m nodeManager) context(ctx Context, v UI) Context {
 	ctx.sourceElement = v
 	ctx.notifyComponentEvent = m.NotifyComponentEvent
+	if c, ok := component(v); ok && c.lifecycle() != nil {
+		ctx.Context = c.lifecycle()
+	}
 	return ctx
 }
 
@@ -634,6 +938,11 @@ func (m nodeManager) NotifyComponentEvent(ctx Context, root UI, event any) {
 			m.NotifyComponentEvent(ctx, child, event)
 		}
 
+	case *portal:
+		for _, child := range element.body() {
+			m.NotifyComponentEvent(ctx, child, event)
+		}
+
 	case Composer:
 		switch event.(type) {
 		case nav:
@@ -680,6 +989,9 @@ func (m nodeManager) encode(ctx Context, w *bytes.Buffer, depth int, v UI) {
 
 	case *raw:
 		m.encodeRawHTML(w, depth, v)
+
+	case *portal:
+		m.encodePortal(ctx, w, depth, v)
 	}
 }
 
@@ -769,6 +1081,14 @@ func (m nodeManager) encodeRawHTML(w *bytes.Buffer, depth int, v *raw) {
 	}
 }
 
+// encodePortal encodes the portal children within a template element, the
+// target element not being known when rendered on the server.
+func (m nodeManager) encodePortal(ctx Context, w *bytes.Buffer, depth int, v *portal) {
+	m.encode(ctx, w, depth, Template().
+		DataSet("goapp-portal", v.target).
+		Body(v.children...))
+}
+
 func canUpdateValue(v, new reflect.Value) bool {
 	switch v.Kind() {
 	case reflect.String,
@@ -798,6 +1118,21 @@ func canUpdateValue(v, new reflect.Value) bool {
 	}
 }
 
+// Ancestor returns the closest ancestor of the given element that is of type
+// T. The element itself is not considered.
+func Ancestor[T UI](v UI) (T, bool) {
+	if v != nil {
+		for element := v.parent(); element != nil; element = element.parent() {
+			if ancestor, ok := element.(T); ok {
+				return ancestor, true
+			}
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
 func component(v UI) (Composer, bool) {
 	for element := v; element != nil; element = element.parent() {
 		if component, ok := element.(Composer); ok {
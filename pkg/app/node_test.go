@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/maxence-charriere/go-app/v11/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -937,6 +938,156 @@ func TestNodeManagerContext(t *testing.T) {
 	require.NotNil(t, ctx.notifyComponentEvent)
 }
 
+func TestNodeManagerComponentLifecycle(t *testing.T) {
+	var m nodeManager
+	ctx := makeTestContext()
+
+	compo := &foo{Bar: "bar"}
+	div, err := m.Mount(ctx, 1, Div().Body(compo))
+	require.NoError(t, err)
+
+	compoCtx := m.context(ctx, compo)
+	require.NotEqual(t, ctx.Context, compoCtx.Context)
+	require.NoError(t, compoCtx.Err())
+
+	child := compo.root().(*bar)
+	childCtx := m.context(ctx, child)
+	require.NotEqual(t, compoCtx.Context, childCtx.Context)
+
+	textCtx := m.context(ctx, child.root())
+	require.Equal(t, childCtx.Context, textCtx.Context)
+
+	m.Dismount(div)
+	require.Error(t, compoCtx.Err())
+	require.Error(t, childCtx.Err())
+	require.NoError(t, ctx.Err())
+	require.Nil(t, compo.lifecycle())
+
+	m.Mount(ctx, 1, compo)
+	require.NoError(t, m.context(ctx, compo).Err())
+}
+
+func TestNodeManagerErrorBoundary(t *testing.T) {
+	t.Run("render panic is converted into an error", func(t *testing.T) {
+		var m nodeManager
+		_, err := m.Mount(makeTestContext(), 1, &panicComponent{Panic: true})
+		require.Error(t, err)
+	})
+
+	t.Run("mount failure renders the fallback", func(t *testing.T) {
+		var m nodeManager
+		boundary := &errorBoundaryComponent{Child: &panicComponent{Panic: true}}
+
+		_, err := m.Mount(makeTestContext(), 1, boundary)
+		require.NoError(t, err)
+		require.Error(t, boundary.err)
+		require.IsType(t, P(), boundary.root())
+		require.Equal(t, boundary, boundary.root().parent())
+	})
+
+	t.Run("mount failure dismounts the mounted elements", func(t *testing.T) {
+		var m nodeManager
+		ctx := makeTestContext()
+		mounted := &panicComponent{}
+		failing := &panicComponent{Panic: true}
+		div := Div().Body(mounted, failing)
+		boundary := &errorBoundaryComponent{Child: div}
+
+		_, err := m.Mount(ctx, 1, boundary)
+		require.NoError(t, err)
+		require.Error(t, boundary.err)
+		require.IsType(t, P(), boundary.root())
+		require.False(t, div.Mounted())
+		require.False(t, mounted.Mounted())
+		require.Nil(t, mounted.lifecycle())
+		require.False(t, failing.Mounted())
+		require.Nil(t, failing.lifecycle())
+	})
+
+	t.Run("update failure renders the fallback", func(t *testing.T) {
+		var m nodeManager
+		ctx := makeTestContext()
+		child := &panicComponent{}
+		boundary := &errorBoundaryComponent{Child: child}
+
+		div, err := m.Mount(ctx, 1, Div().Body(boundary))
+		require.NoError(t, err)
+		require.NoError(t, boundary.err)
+		root := boundary.root()
+
+		boundary.Child = &panicComponent{Panic: true}
+		_, err = m.UpdateComponentRoot(ctx, boundary)
+		require.NoError(t, err)
+		require.Error(t, boundary.err)
+		require.IsType(t, P(), boundary.root())
+		require.False(t, root.Mounted())
+		require.False(t, child.Mounted())
+		require.True(t, div.Mounted())
+	})
+
+	t.Run("update hook panic renders the fallback", func(t *testing.T) {
+		var m nodeManager
+		ctx := makeTestContext()
+		child := &updatePanicComponent{}
+		boundary := &errorBoundaryComponent{Child: child}
+
+		_, err := m.Mount(ctx, 1, boundary)
+		require.NoError(t, err)
+		require.NoError(t, boundary.err)
+
+		boundary.Child = &updatePanicComponent{Value: 42}
+		_, err = m.UpdateComponentRoot(ctx, boundary)
+		require.NoError(t, err)
+		require.Error(t, boundary.err)
+		require.IsType(t, P(), boundary.root())
+		require.False(t, child.Mounted())
+	})
+
+	t.Run("component failure is caught by the closest boundary", func(t *testing.T) {
+		var m nodeManager
+		ctx := makeTestContext()
+		child := &panicComponent{}
+		boundary := &errorBoundaryComponent{Child: child}
+
+		_, err := m.Mount(ctx, 1, boundary)
+		require.NoError(t, err)
+
+		child.Panic = true
+		_, err = m.UpdateComponentRoot(ctx, child)
+		require.Error(t, err)
+
+		err = m.CatchError(ctx, child, err)
+		require.NoError(t, err)
+		require.Error(t, boundary.err)
+		require.IsType(t, P(), boundary.root())
+	})
+
+	t.Run("failure without boundary is returned", func(t *testing.T) {
+		var m nodeManager
+		ctx := makeTestContext()
+		child := &panicComponent{}
+
+		_, err := m.Mount(ctx, 1, Div().Body(child))
+		require.NoError(t, err)
+
+		child.Panic = true
+		_, err = m.UpdateComponentRoot(ctx, child)
+		require.Error(t, err)
+		require.Error(t, m.CatchError(ctx, child, err))
+	})
+
+	t.Run("boundary does not catch its own failure", func(t *testing.T) {
+		var m nodeManager
+		ctx := makeTestContext()
+		boundary := &errorBoundaryComponent{}
+
+		_, err := m.Mount(ctx, 1, boundary)
+		require.NoError(t, err)
+		require.Error(t, m.CatchError(ctx, boundary, errors.New("test")))
+		require.NoError(t, boundary.err)
+	})
+}
+
 func TestNodeManagerNotifyComponentEvent(t *testing.T) {
 	ctx := makeTestContext()
 
@@ -1294,3 +1445,32 @@ func TestComponent(t *testing.T) {
 		require.Nil(t, c)
 	})
 }
+
+func TestAncestor(t *testing.T) {
+	child := &bar{}
+	compo := &compoWithCustomRoot{Root: Div().Body(Span().Body(child))}
+
+	var m nodeManager
+	_, err := m.Mount(makeTestContext(), 1, compo)
+	require.NoError(t, err)
+
+	t.Run("closest ancestor is returned", func(t *testing.T) {
+		span, ok := Ancestor[HTML](child)
+		require.True(t, ok)
+		require.IsType(t, &htmlSpan{}, span)
+
+		c, ok := Ancestor[*compoWithCustomRoot](child)
+		require.True(t, ok)
+		require.Equal(t, compo, c)
+	})
+
+	t.Run("element itself is not returned", func(t *testing.T) {
+		_, ok := Ancestor[*compoWithCustomRoot](compo)
+		require.False(t, ok)
+	})
+
+	t.Run("nil element", func(t *testing.T) {
+		_, ok := Ancestor[Composer](nil)
+		require.False(t, ok)
+	})
+}
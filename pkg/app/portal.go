@@ -0,0 +1,88 @@
+package app
+
+// Portal returns an element that renders the given children into the element
+// with the given ID rather than at its position in the DOM. It is useful to
+// display modals, toasts, or tooltips that must not be clipped by the overflow
+// of their ancestors. The children are rendered into the body when the target
+// ID is empty.
+//
+// Portal children remain part of the component tree where the portal is
+// declared: they share the lifecycle, the context and the updates of their
+// enclosing components, and are removed from the target when the portal is
+// dismounted.
+//
+// The target element must exist when the portal is mounted. Targeting an
+// element declared in the page body rather than the body itself keeps portals
+// compatible with KeepBodyClean.
+//
+// Example:
+//
+//	app.Portal("overlay-root",
+//		app.Div().Class("modal").Text("Hello"),
+//	)
+func Portal(targetID string, children ...UI) UI {
+	return &portal{
+		target:   targetID,
+		children: FilterUIElems(children...),
+	}
+}
+
+// portal is an element that is represented in the DOM by an empty template
+// placeholder, its children being mounted in a container that is appended to
+// the target element.
+type portal struct {
+	jsElement     Value
+	container     Value
+	parentElement UI
+	treeDepth     uint
+	target        string
+	children      []UI
+}
+
+func (p *portal) JSValue() Value {
+	return p.jsElement
+}
+
+func (p *portal) Mounted() bool {
+	return p.jsElement != nil
+}
+
+func (p *portal) depth() uint {
+	return p.treeDepth
+}
+
+func (p *portal) parent() UI {
+	return p.parentElement
+}
+
+func (p *portal) setParent(v UI) UI {
+	p.parentElement = v
+	return p
+}
+
+func (p *portal) body() []UI {
+	return p.children
+}
+
+// targetElement returns the element where the portal children are rendered.
+func (p *portal) targetElement() Value {
+	if p.target == "" {
+		return Window().Get("document").Get("body")
+	}
+	return Window().GetElementByID(p.target)
+}
+
+// containerElement returns the javascript element that holds the DOM nodes of
+// the children of the given element.
+func containerElement(v UI) (Value, bool) {
+	switch v := v.(type) {
+	case HTML:
+		return v.JSValue(), true
+
+	case *portal:
+		return v.container, true
+
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortal(t *testing.T) {
+	testSkipWasm(t)
+	ctx := makeTestContext()
+
+	t.Run("portal is mounted", func(t *testing.T) {
+		var m nodeManager
+
+		div, err := m.Mount(ctx, 1, Div().Body(
+			Portal("overlay", Span(), &hello{}),
+		))
+		require.NoError(t, err)
+
+		p := div.(HTML).body()[0].(*portal)
+		require.True(t, p.Mounted())
+		require.NotNil(t, p.container)
+		require.Equal(t, div, p.parent())
+		require.Equal(t, uint(2), p.depth())
+
+		span := p.body()[0]
+		require.True(t, span.Mounted())
+		require.Equal(t, p, span.parent())
+
+		compo := p.body()[1].(*hello)
+		require.True(t, compo.Mounted())
+		require.Equal(t, p, compo.parent())
+
+		c, ok := component(span)
+		require.False(t, ok)
+		require.Nil(t, c)
+	})
+
+	t.Run("portal is dismounted", func(t *testing.T) {
+		var m nodeManager
+
+		p, err := m.Mount(ctx, 1, Portal("", Span(), &hello{}))
+		require.NoError(t, err)
+		span := p.(*portal).body()[0]
+		compo := p.(*portal).body()[1]
+
+		m.Dismount(p)
+		require.False(t, p.Mounted())
+		require.Nil(t, p.(*portal).container)
+		require.False(t, span.Mounted())
+		require.False(t, compo.Mounted())
+	})
+
+	t.Run("portal mount failure dismounts the mounted children", func(t *testing.T) {
+		var m nodeManager
+
+		mounted := &panicComponent{}
+		failing := &panicComponent{Panic: true}
+		p := Portal("", mounted, failing)
+
+		_, err := m.Mount(ctx, 1, p)
+		require.Error(t, err)
+		require.False(t, p.Mounted())
+		require.Nil(t, p.(*portal).container)
+		require.False(t, mounted.Mounted())
+		require.Nil(t, mounted.lifecycle())
+		require.False(t, failing.Mounted())
+	})
+
+	t.Run("mounted portal is not mounted", func(t *testing.T) {
+		var m nodeManager
+
+		p, err := m.Mount(ctx, 1, Portal(""))
+		require.NoError(t, err)
+
+		_, err = m.Mount(ctx, 1, p)
+		require.Error(t, err)
+	})
+
+	t.Run("portal with same target can be updated", func(t *testing.T) {
+		var m nodeManager
+		require.True(t, m.CanUpdate(Portal("a"), Portal("a")))
+		require.False(t, m.CanUpdate(Portal("a"), Portal("b")))
+	})
+
+	t.Run("portal is updated", func(t *testing.T) {
+		var m nodeManager
+
+		p, err := m.Mount(ctx, 1, Portal("overlay", Span(), Div()))
+		require.NoError(t, err)
+		span := p.(*portal).body()[0]
+
+		p, err = m.Update(ctx, p, Portal("overlay",
+			Span().Class("updated"),
+			P(),
+			Text("hi"),
+		))
+		require.NoError(t, err)
+
+		children := p.(*portal).body()
+		require.Len(t, children, 3)
+		require.Equal(t, span, children[0])
+		require.Equal(t, "updated", span.(HTML).attrs()["class"])
+		require.IsType(t, P(), children[1])
+		require.True(t, children[1].Mounted())
+		require.Equal(t, p, children[1].parent())
+		require.True(t, children[2].Mounted())
+	})
+
+	t.Run("component root within portal is replaced", func(t *testing.T) {
+		e := newTestEngine()
+		compo := &foo{Bar: "bar"}
+		require.NoError(t, e.Load(&errorBoundaryComponent{
+			Child: Portal("overlay", compo),
+		}))
+
+		compo.Bar = ""
+		_, err := e.nodes.UpdateComponentRoot(e.baseContext(), compo)
+		require.NoError(t, err)
+		require.True(t, compo.root().Mounted())
+	})
+
+	t.Run("portal is encoded", func(t *testing.T) {
+		html := HTMLString(Div().Body(
+			Portal("overlay", Span().Text("hello")),
+		))
+		require.Contains(t, html, `<template data-goapp-portal="overlay">`)
+		require.Contains(t, html, "<span>hello</span>")
+	})
+
+	t.Run("portal is matched", func(t *testing.T) {
+		tree := Div().Body(
+			Portal("overlay", Span().Text("hello")),
+		)
+		require.NoError(t, Match(Portal("overlay"), tree, 0))
+		require.Error(t, Match(Portal("root"), tree, 0))
+		require.NoError(t, Match(Text("hello"), tree, 0, 0, 0))
+	})
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/maxence-charriere/go-app/v11/pkg/errors"
+)
+
+const (
+	// The tags of the errors.Error values that describe where a failure
+	// occurred and whether it is a recovered panic.
+	componentTypeErrorTag  = "component-type"
+	componentDepthErrorTag = "component-depth"
+	panickedErrorTag       = "panicked"
+)
+
+var (
+	errorReporter func(ErrorReport)
+)
+
+// ErrorReport describes an error logged with Log or Logf, an error caught by an
+// ErrorBoundary, or a recovered panic.
+type ErrorReport struct {
+	// Err is the reported error. Recovered panics are wrapped into an error
+	// with a "panic" tag.
+	Err error
+
+	// Panic reports whether the error comes from a recovered panic.
+	Panic bool
+
+	// ComponentType is the type of the component where the error occurred.
+	// It is empty when the error is not related to a component.
+	ComponentType string
+
+	// Depth is the depth of the component where the error occurred.
+	Depth uint
+
+	// Tags are the tags of the errors.Error values in the error chain. Tags of
+	// wrapped errors take precedence over the tags of the errors that wrap
+	// them.
+	Tags map[string]any
+}
+
+// SetErrorReporter sets the function called with every error logged with Log
+// or Logf, caught by an ErrorBoundary, or recovered from a panic, including
+// the panics that stop the app. It is useful to send errors to a crash
+// reporting service and must be called before the app is started.
+//
+// The function is called synchronously and must not log errors itself.
+func SetErrorReporter(f func(ErrorReport)) {
+	errorReporter = f
+}
+
+func reportError(r ErrorReport) {
+	if errorReporter == nil || r.Err == nil {
+		return
+	}
+
+	if r.Tags == nil {
+		r.Tags = errorTags(r.Err)
+	}
+	errorReporter(r)
+}
+
+// reportComponentError reports an error caught in the component that contains
+// the given element. The component where the error occurred and whether it is
+// a recovered panic are read from the error tags set when it was created.
+func reportComponentError(v UI, err error) {
+	if errorReporter == nil {
+		return
+	}
+
+	panicked, _ := errors.Tag(err, panickedErrorTag).(bool)
+	report := ErrorReport{
+		Err:   err,
+		Panic: panicked,
+	}
+
+	if componentType, ok := errors.Tag(err, componentTypeErrorTag).(string); ok {
+		report.ComponentType = componentType
+		report.Depth, _ = errors.Tag(err, componentDepthErrorTag).(uint)
+	} else if c, ok := component(v); ok {
+		report.ComponentType = fmt.Sprintf("%T", c)
+		report.Depth = c.depth()
+	}
+	reportError(report)
+}
+
+func errorTags(err error) map[string]any {
+	var chain []errors.Error
+	for ; err != nil; err = errors.Unwrap(err) {
+		if e, ok := err.(errors.Error); ok {
+			chain = append(chain, e)
+		}
+	}
+
+	var tags map[string]any
+	for i := len(chain) - 1; i >= 0; i-- {
+		for k, v := range chain[i].Tags {
+			if tags == nil {
+				tags = make(map[string]any)
+			}
+			if _, ok := tags[k]; !ok {
+				tags[k] = v
+			}
+		}
+	}
+	return tags
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v11/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func testErrorReports(t *testing.T) *[]ErrorReport {
+	var reports []ErrorReport
+	SetErrorReporter(func(r ErrorReport) {
+		reports = append(reports, r)
+	})
+	t.Cleanup(func() { SetErrorReporter(nil) })
+	return &reports
+}
+
+func TestReportError(t *testing.T) {
+	t.Run("no reporter", func(t *testing.T) {
+		reportError(ErrorReport{Err: errors.New("test")})
+	})
+
+	t.Run("nil error is not reported", func(t *testing.T) {
+		reports := testErrorReports(t)
+		reportError(ErrorReport{})
+		require.Empty(t, *reports)
+	})
+
+	t.Run("logged error is reported", func(t *testing.T) {
+		reports := testErrorReports(t)
+
+		Log("hello")
+		require.Empty(t, *reports)
+
+		err := errors.New("test").WithTag("foo", "bar")
+		Log(err)
+		require.Len(t, *reports, 1)
+		require.Equal(t, err, (*reports)[0].Err)
+		require.False(t, (*reports)[0].Panic)
+		require.Equal(t, "bar", (*reports)[0].Tags["foo"])
+	})
+
+	t.Run("component error is reported", func(t *testing.T) {
+		reports := testErrorReports(t)
+
+		var m nodeManager
+		boundary := &errorBoundaryComponent{Child: &panicComponent{Panic: true}}
+		_, err := m.Mount(makeTestContext(), 1, Div().Body(boundary))
+		require.NoError(t, err)
+
+		require.Len(t, *reports, 1)
+		report := (*reports)[0]
+		require.Error(t, report.Err)
+		require.True(t, report.Panic)
+		require.Equal(t, "*app.panicComponent", report.ComponentType)
+		require.Equal(t, uint(4), report.Depth)
+		require.Equal(t, "render panic", report.Tags["panic"])
+	})
+
+	t.Run("component error is reported with its tags", func(t *testing.T) {
+		reports := testErrorReports(t)
+
+		boundary := &errorBoundaryComponent{}
+		reportComponentError(boundary, errors.New("outer").Wrap(
+			errors.New("inner").
+				WithTag(componentTypeErrorTag, "*app.hello").
+				WithTag(componentDepthErrorTag, uint(3)),
+		))
+		reportComponentError(boundary, errors.New("test").WithTag("panic", "not a panic"))
+
+		require.Len(t, *reports, 2)
+		require.Equal(t, "*app.hello", (*reports)[0].ComponentType)
+		require.Equal(t, uint(3), (*reports)[0].Depth)
+		require.False(t, (*reports)[0].Panic)
+		require.False(t, (*reports)[1].Panic)
+	})
+
+	t.Run("dispatch panic is reported", func(t *testing.T) {
+		reports := testErrorReports(t)
+
+		e := newTestEngine()
+		child := &hello{}
+		boundary := &errorBoundaryComponent{Child: child}
+		require.NoError(t, e.Load(boundary))
+		e.ConsumeAll()
+
+		e.nodes.context(e.baseContext(), child).Dispatch(func(ctx Context) {
+			panic("handler panic")
+		})
+		e.ConsumeAll()
+
+		require.Len(t, *reports, 1)
+		require.True(t, (*reports)[0].Panic)
+		require.Equal(t, "*app.hello", (*reports)[0].ComponentType)
+	})
+}
+
+func TestErrorTags(t *testing.T) {
+	require.Nil(t, errorTags(nil))
+
+	err := errors.New("outer").
+		WithTag("foo", "outer").
+		WithTag("outer", true).
+		Wrap(errors.New("inner").
+			WithTag("foo", "inner"))
+
+	require.Equal(t, map[string]any{
+		"foo":   "inner",
+		"outer": true,
+	}, errorTags(err))
+}
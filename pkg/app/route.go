@@ -68,6 +68,21 @@ func (r *router) createComponent(path string) (Composer, bool) {
 	return nil, false
 }
 
+// createComponents creates a component for each route.
+func (r *router) createComponents() []Composer {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	components := make([]Composer, 0, len(r.routes)+len(r.routesWithRegexp))
+	for _, newComponent := range r.routes {
+		components = append(components, newComponent())
+	}
+	for _, rwr := range r.routesWithRegexp {
+		components = append(components, rwr.newComponent())
+	}
+	return components
+}
+
 type regexpRoute struct {
 	regexp       *regexp.Regexp
 	newComponent func() Composer
@@ -3,6 +3,7 @@ package app
 import (
 	"encoding/json"
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 
@@ -34,31 +35,408 @@ func (s State) ExpiresAt(v time.Time) State {
 	return s.expire(s, v)
 }
 
-// Persist ensures the state is persisted into the local storage.
+// Persist ensures the state is persisted into the storage configured with
+// ConfigureState, which is the local storage by default.
 func (s State) Persist() State {
 	return s.persist(s, false)
 }
 
-// PersistWithEncryption ensures the state is persisted into the local storage
-// with encryption.
+// PersistWithEncryption ensures the state is persisted into the storage
+// configured with ConfigureState with encryption.
 func (s State) PersistWithEncryption() State {
 	return s.persist(s, true)
 }
 
 // Broadcast signals that changes to the state will be broadcasted to other
 // browser tabs and windows sharing the same origin when it is supported.
+// Conflicting changes are resolved by keeping the last written value.
 //
-// Using Broadcast creates a BroadcastChannel, which prevents the page from
-// being cached. This may impact the Chrome Lighthouse performance score due to
-// the additional resources required to manage the broadcast channel.
+// With the default BroadcastChannelMode, using Broadcast creates a
+// BroadcastChannel, which prevents the page from being cached. This may impact
+// the Chrome Lighthouse performance score due to the additional resources
+// required to manage the broadcast channel. Use SetBroadcastMode with
+// StorageEventMode to avoid it.
 func (s State) Broadcast() State {
 	return s.broadcast(s)
 }
 
+// StateKey is a typed handle to a state. It provides compile-time typed access
+// to a state while relying on the same mechanisms as Context.GetState,
+// Context.SetState, and Context.ObserveState.
+//
+// Example:
+//
+//	var cart = app.NewStateKey[Cart]("cart")
+//
+//	func (c *cartButton) OnMount(ctx app.Context) {
+//		cart.Observe(ctx, &c.cart)
+//	}
+//
+//	func (c *cartButton) addItem(ctx app.Context, item Item) {
+//		v := cart.Get(ctx)
+//		v.Items = append(v.Items, item)
+//		cart.Set(ctx, v).Persist()
+//	}
+type StateKey[T any] struct {
+	name string
+}
+
+// NewStateKey returns a typed handle to the named state.
+func NewStateKey[T any](name string) StateKey[T] {
+	return StateKey[T]{name: name}
+}
+
+// Name returns the state name.
+func (k StateKey[T]) Name() string {
+	return k.name
+}
+
+// Get returns the state value.
+func (k StateKey[T]) Get(ctx Context) T {
+	var v T
+	ctx.GetState(k.name, &v)
+	return v
+}
+
+// Set modifies the state with the provided value. The returned state offers
+// methods to expire, persist, or broadcast the state.
+func (k StateKey[T]) Set(ctx Context, v T) State {
+	return ctx.SetState(k.name, v)
+}
+
+// Observe establishes an observer for the state that stores its value into
+// the given receiver each time it changes.
+func (k StateKey[T]) Observe(ctx Context, recv *T) Observer {
+	return ctx.ObserveState(k.name, recv)
+}
+
+// Del erases the state, halting all associated observations.
+func (k StateKey[T]) Del(ctx Context) {
+	ctx.DelState(k.name)
+}
+
+// Derive declares the state as computed from the given dependencies. See
+// Context.DeriveState.
+func (k StateKey[T]) Derive(ctx Context, compute func(Context) T, dependencies ...string) {
+	ctx.DeriveState(k.name, func(ctx Context) any {
+		return compute(ctx)
+	}, dependencies...)
+}
+
+// Configure returns the state configuration. See ConfigureState.
+func (k StateKey[T]) Configure() StateConfig {
+	return ConfigureState(k.name)
+}
+
+// BroadcastMode defines how broadcasted states are synchronized across browser
+// tabs and windows.
+type BroadcastMode int
+
+const (
+	// BroadcastChannelMode synchronizes states with a BroadcastChannel. It is
+	// the default mode.
+	BroadcastChannelMode BroadcastMode = iota
+
+	// StorageEventMode synchronizes states with local storage events.
+	// Unlike BroadcastChannelMode, it does not prevent pages from being stored
+	// in the back/forward cache.
+	StorageEventMode
+)
+
+// SetBroadcastMode sets how broadcasted states are synchronized across browser
+// tabs and windows. It must be called before the app is started.
+func SetBroadcastMode(v BroadcastMode) {
+	stateConfigs.setBroadcastMode(v)
+}
+
+const (
+	broadcastStorageKeyPrefix = "/go-app/broadcast/"
+)
+
+type broadcastMessage struct {
+	StoreID   string
+	State     string
+	Value     string
+	Timestamp int64
+}
+
+// broadcastValue is a JSON encoded state value received from another browser
+// tab or window.
+type broadcastValue []byte
+
+// stateVersion identifies a state change in order to resolve conflicts
+// between browser tabs and windows. The last written change wins.
+type stateVersion struct {
+	Timestamp int64
+	StoreID   string
+}
+
+func (v stateVersion) before(other stateVersion) bool {
+	if v.Timestamp != other.Timestamp {
+		return v.Timestamp < other.Timestamp
+	}
+	return v.StoreID < other.StoreID
+}
+
+// StateStorage returns the browser storage where a state is persisted.
+type StateStorage func(Context) BrowserStorage
+
+var (
+	// LocalStateStorage persists states into the local storage.
+	LocalStateStorage StateStorage = Context.LocalStorage
+
+	// SessionStateStorage persists states into the session storage.
+	SessionStateStorage StateStorage = Context.SessionStorage
+
+	// IndexedDBStateStorage persists states into IndexedDB.
+	IndexedDBStateStorage StateStorage = func(ctx Context) BrowserStorage {
+		return ctx.IndexedDB()
+	}
+
+	// CookieStateStorage persists states into cookies. Cookies are sent with
+	// page requests, which makes the states readable during server-side
+	// rendering, in OnPreRender for example.
+	CookieStateStorage StateStorage = Context.Cookies
+)
+
+// StateMigration transforms a persisted state value encoded in JSON from a
+// schema version to the next one.
+type StateMigration func(json.RawMessage) (json.RawMessage, error)
+
+// StateConfig describes how a state is handled across the app.
+type StateConfig struct {
+	name         string
+	storage      StateStorage
+	version      int
+	migrations   map[int]StateMigration
+	defaultValue any
+	trackHistory bool
+}
+
+// ConfigureState returns the configuration of the named state. It should be
+// called before the app is started, alongside Route and Handle calls.
+//
+// Example:
+//
+//	app.ConfigureState("preferences").StoreIn(app.CookieStateStorage)
+func ConfigureState(state string) StateConfig {
+	return stateConfigs.get(state)
+}
+
+// StoreIn sets the storage where the state is persisted.
+func (c StateConfig) StoreIn(s StateStorage) StateConfig {
+	c.storage = s
+	return stateConfigs.set(c)
+}
+
+// Version sets the schema version of the state value. Persisted values with an
+// older version are transformed with the migrations set with Migrate when they
+// are loaded. Defaults to 0.
+func (c StateConfig) Version(v int) StateConfig {
+	c.version = v
+	return stateConfigs.set(c)
+}
+
+// Migrate sets the migration that transforms a persisted value from the given
+// schema version to the next one.
+//
+// Example:
+//
+//	app.ConfigureState("cart").
+//		Version(1).
+//		Migrate(0, func(v json.RawMessage) (json.RawMessage, error) {
+//			var items []string
+//			if err := json.Unmarshal(v, &items); err != nil {
+//				return nil, err
+//			}
+//			return json.Marshal(Cart{Items: items})
+//		})
+func (c StateConfig) Migrate(fromVersion int, m StateMigration) StateConfig {
+	migrations := make(map[int]StateMigration, len(c.migrations)+1)
+	for version, migration := range c.migrations {
+		migrations[version] = migration
+	}
+	migrations[fromVersion] = m
+	c.migrations = migrations
+	return stateConfigs.set(c)
+}
+
+// Default sets the value used when the state is not set or when its persisted
+// value cannot be decoded or migrated. It must be of the same type as the
+// state receivers.
+func (c StateConfig) Default(v any) StateConfig {
+	c.defaultValue = v
+	return stateConfigs.set(c)
+}
+
+// TrackHistory records the changes of the state made with SetState and
+// DelState, which can then be reverted with Context.Undo and reapplied with
+// Context.Redo. Changes made within Context.StateTransaction are grouped into a
+// single undo step.
+//
+// Undo and Redo restore the in-memory value of the state and notify its
+// observers. Values are kept as they are set and should not be modified after
+// being set.
+func (c StateConfig) TrackHistory() StateConfig {
+	c.trackHistory = true
+	return stateConfigs.set(c)
+}
+
+func (c StateConfig) migrate(version int, v []byte) ([]byte, error) {
+	if version > c.version {
+		return nil, errors.New("persisted state version is newer than the state version").
+			WithTag("persisted-version", version).
+			WithTag("version", c.version)
+	}
+
+	for ; version < c.version; version++ {
+		migration, ok := c.migrations[version]
+		if !ok {
+			return nil, errors.New("state migration not found").
+				WithTag("from-version", version)
+		}
+
+		migrated, err := migration(v)
+		if err != nil {
+			return nil, errors.New("migrating state failed").
+				WithTag("from-version", version).
+				Wrap(err)
+		}
+		v = migrated
+	}
+	return v, nil
+}
+
+// SetDefaultStateStorage sets the storage where states without a storage set
+// with ConfigureState are persisted. Defaults to LocalStateStorage.
+func SetDefaultStateStorage(s StateStorage) {
+	stateConfigs.setDefaultStorage(s)
+}
+
+var stateConfigs = &stateConfigRegistry{}
+
+// stateConfigRegistry stores the state configurations. A nil registry
+// provides the default configurations.
+type stateConfigRegistry struct {
+	mutex          sync.RWMutex
+	defaultStorage StateStorage
+	broadcastMode  BroadcastMode
+	historyLimit   int
+	configs        map[string]StateConfig
+}
+
+func (r *stateConfigRegistry) get(state string) StateConfig {
+	if r == nil {
+		return StateConfig{name: state}
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if c, ok := r.configs[state]; ok {
+		return c
+	}
+	return StateConfig{name: state}
+}
+
+func (r *stateConfigRegistry) set(c StateConfig) StateConfig {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.configs == nil {
+		r.configs = make(map[string]StateConfig)
+	}
+	r.configs[c.name] = c
+	return c
+}
+
+func (r *stateConfigRegistry) setDefaultStorage(s StateStorage) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.defaultStorage = s
+}
+
+func (r *stateConfigRegistry) setBroadcastMode(v BroadcastMode) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.broadcastMode = v
+}
+
+func (r *stateConfigRegistry) getBroadcastMode() BroadcastMode {
+	if r == nil {
+		return BroadcastChannelMode
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.broadcastMode
+}
+
+func (r *stateConfigRegistry) setHistoryLimit(v int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.historyLimit = v
+}
+
+func (r *stateConfigRegistry) getHistoryLimit() int {
+	if r == nil {
+		return defaultStateHistoryLimit
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if r.historyLimit <= 0 {
+		return defaultStateHistoryLimit
+	}
+	return r.historyLimit
+}
+
+// storage returns the browser storage where the given state is persisted.
+func (r *stateConfigRegistry) storage(ctx Context, state string) BrowserStorage {
+	if storage := r.get(state).storage; storage != nil {
+		return storage(ctx)
+	}
+	return r.defaultStorageOf(ctx)
+}
+
+func (r *stateConfigRegistry) defaultStorageOf(ctx Context) BrowserStorage {
+	if r != nil {
+		r.mutex.RLock()
+		storage := r.defaultStorage
+		r.mutex.RUnlock()
+
+		if storage != nil {
+			return storage(ctx)
+		}
+	}
+	return LocalStateStorage(ctx)
+}
+
+// storages returns the default storage followed by the storages set with
+// ConfigureState.
+func (r *stateConfigRegistry) storages(ctx Context) []BrowserStorage {
+	storages := []BrowserStorage{r.defaultStorageOf(ctx)}
+	if r == nil {
+		return storages
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, c := range r.configs {
+		if c.storage != nil {
+			storages = append(storages, c.storage(ctx))
+		}
+	}
+	return storages
+}
+
 type storableState struct {
 	Value          json.RawMessage `json:",omitempty"`
 	EncryptedValue []byte          `json:",omitempty"`
 	ExpiresAt      time.Time       `json:",omitempty"`
+	Version        int             `json:",omitempty"`
 }
 
 // Observer represents a mechanism to monitor and react to changes in a state.
@@ -94,10 +472,10 @@ func (o Observer) OnChange(h func()) Observer {
 // ynchronizing state across multiple open instances of a web application within
 // the same browser.
 //
-// Calling WithBroadcast creates a BroadcastChannel, which prevents the page
-// from being cached. This may impact the Chrome Lighthouse performance
-// score due to the additional resources required to manage the broadcast
-// channel.
+// With the default BroadcastChannelMode, calling WithBroadcast creates a
+// BroadcastChannel, which prevents the page from being cached. This may impact
+// the Chrome Lighthouse performance score due to the additional resources
+// required to manage the broadcast channel. See SetBroadcastMode.
 func (o Observer) WithBroadcast() Observer {
 	o.enableBroadcast()
 	o.broadcast = true
@@ -124,6 +502,20 @@ type stateManager struct {
 	initBroadcastOnce sync.Once
 	broadcastStoreID  string
 	broadcastChannel  Value
+	configs           *stateConfigRegistry
+	derivedStates     map[string]*derivedState
+	dependents        map[string]map[string]struct{}
+	versions          map[string]stateVersion
+	history           stateHistory
+	debug             *debugRecorder
+}
+
+// derivedState describes a state computed from other states.
+type derivedState struct {
+	compute      func(Context) any
+	dependencies []string
+	dirty        bool
+	computing    bool
 }
 
 // Observe initiates observation for a specified state, ensuring the state
@@ -168,13 +560,17 @@ func (m *stateManager) setObserver(v Observer) Observer {
 // Get retrieves the value of a specific state, setting it to the provided
 // receiver.
 func (m *stateManager) Get(ctx Context, state string, receiver any) {
+	if m.dirtyDerivedState(state) {
+		m.computeDerivedState(ctx, state)
+	}
+
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
 	value, exists := m.states[state]
 	if !exists {
 		if err := m.getStoredState(ctx, state, receiver); err != nil {
-			Log(errors.New("getting state from local storage failed").
+			Log(errors.New("getting state from storage failed").
 				WithTag("state", state).
 				Wrap(err))
 		}
@@ -183,7 +579,16 @@ func (m *stateManager) Get(ctx Context, state string, receiver any) {
 
 	if expiredTime(value.expiresAt) {
 		delete(m.states, state)
-		ctx.LocalStorage().Del(state)
+		m.configs.storage(ctx, state).Del(state)
+		return
+	}
+
+	if v, ok := value.value.(broadcastValue); ok {
+		if err := json.Unmarshal(v, receiver); err != nil {
+			Log(errors.New("getting broadcasted state failed").
+				WithTag("state", state).
+				Wrap(err))
+		}
 		return
 	}
 
@@ -195,25 +600,108 @@ func (m *stateManager) Get(ctx Context, state string, receiver any) {
 }
 
 func (m *stateManager) getStoredState(ctx Context, state string, receiver any) error {
+	config := m.configs.get(state)
+	storage := m.configs.storage(ctx, state)
+
 	var value storableState
-	if err := ctx.LocalStorage().Get(state, &value); err != nil {
-		return err
+	if err := storage.Get(state, &value); err != nil {
+		return m.fallbackStoredState(nil, config, receiver, err)
 	}
 
 	if expiredTime(value.ExpiresAt) {
-		ctx.LocalStorage().Del(state)
-		return nil
+		storage.Del(state)
+		return m.fallbackStoredState(nil, config, receiver, nil)
+	}
+
+	encrypted := len(value.EncryptedValue) != 0
+	payload := []byte(value.Value)
+	if encrypted {
+		b, err := decrypt(ctx.cryptoKey(), value.EncryptedValue)
+		if err != nil {
+			return m.fallbackStoredState(nil, config, receiver, errors.New("decrypting value failed").Wrap(err))
+		}
+		payload = b
+	}
+	if len(payload) == 0 {
+		return m.fallbackStoredState(nil, config, receiver, nil)
+	}
+
+	if value.Version != config.version {
+		migrated, err := config.migrate(value.Version, payload)
+		if err != nil {
+			return m.fallbackStoredState(storage, config, receiver, err)
+		}
+		payload = migrated
+
+		if err := m.storeMigratedState(ctx, storage, state, value, encrypted, payload, config.version); err != nil {
+			Log(errors.New("storing migrated state failed").
+				WithTag("state", state).
+				Wrap(err))
+		}
+	}
+
+	if err := json.Unmarshal(payload, receiver); err != nil {
+		return m.fallbackStoredState(storage, config, receiver, err)
+	}
+	return nil
+}
+
+func (m *stateManager) storeMigratedState(ctx Context, storage BrowserStorage, state string, value storableState, encrypt bool, payload []byte, version int) error {
+	value.Version = version
+	if encrypt {
+		b, err := ctx.Encrypt(json.RawMessage(payload))
+		if err != nil {
+			return err
+		}
+		value.EncryptedValue = b
+	} else {
+		value.Value = payload
+	}
+	return storage.Set(state, value)
+}
+
+// fallbackStoredState sets the receiver with the state default value when a
+// persisted state is missing or unreadable, otherwise the given error is
+// returned. When a storage is given, the unreadable value is removed from it
+// once replaced by the default value. It is given only for values that cannot
+// be decoded or migrated: values that cannot be read from the storage or
+// decrypted, such as when the storage is not loaded or the key changed, are
+// kept.
+func (m *stateManager) fallbackStoredState(storage BrowserStorage, config StateConfig, receiver any, err error) error {
+	if config.defaultValue == nil {
+		return err
+	}
+
+	if storage != nil {
+		storage.Del(config.name)
 	}
 
-	if len(value.EncryptedValue) != 0 {
-		return ctx.Decrypt(value.EncryptedValue, receiver)
-	} else if len(value.Value) != 0 {
-		return json.Unmarshal(value.Value, receiver)
+	if err := storeValue(receiver, config.defaultValue); err != nil {
+		return errors.New("setting state default value failed").Wrap(err)
 	}
 	return nil
 }
 
+// Set updates a specified state with a new value and notifies its observers.
+// States derived from the updated state are invalidated. It returns a state
+// object, offering methods for advanced state manipulations.
 func (m *stateManager) Set(ctx Context, state string, v any) State {
+	before, tracked := m.historySnapshot(state)
+	s := m.set(ctx, state, v)
+	if tracked {
+		m.trackChange(stateChange{
+			state:  state,
+			before: before,
+			after:  stateSnapshot{value: v, set: true},
+		})
+	}
+
+	m.debug.Record(ctx, DebugStateSet, state, v, nil)
+	m.invalidateDependents(ctx, state)
+	return s
+}
+
+func (m *stateManager) set(ctx Context, state string, v any) State {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -223,6 +711,10 @@ func (m *stateManager) Set(ctx Context, state string, v any) State {
 
 	value := State{value: v}
 	m.states[state] = value
+	m.setVersion(state, stateVersion{
+		Timestamp: time.Now().UnixMicro(),
+		StoreID:   m.storeID(),
+	})
 
 	for _, observer := range m.observers[state] {
 		o := observer
@@ -269,8 +761,6 @@ func (m *stateManager) Set(ctx Context, state string, v any) State {
 	}
 }
 
-// Set updates a specified state with a new value and notifies its observers.
-// It returns a state object, offering methods for advanced state manipulations.
 func (m *stateManager) setExpiration(s State, v time.Time) State {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -288,7 +778,10 @@ func (m *stateManager) persist(s State, encrypt bool) State {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	value := storableState{ExpiresAt: s.expiresAt}
+	value := storableState{
+		ExpiresAt: s.expiresAt,
+		Version:   m.configs.get(s.name).version,
+	}
 	if encrypt {
 		b, err := s.ctx.Encrypt(s.value)
 		if err != nil {
@@ -309,7 +802,7 @@ func (m *stateManager) persist(s State, encrypt bool) State {
 		value.Value = b
 	}
 
-	if err := s.ctx.LocalStorage().Set(s.name, value); err != nil {
+	if err := m.configs.storage(s.ctx, s.name).Set(s.name, value); err != nil {
 		Log(errors.New("persisting state failed").
 			WithTag("state", s.name).
 			Wrap(err))
@@ -323,12 +816,6 @@ func (m *stateManager) broadcast(s State) State {
 
 	m.initBroadcast(s.ctx)
 
-	if m.broadcastChannel == nil {
-		Log(errors.New("broadcast not supported").
-			WithTag("state", s.name))
-		return s
-	}
-
 	b, err := json.Marshal(s.value)
 	if err != nil {
 		Log(errors.New("encoding broadcast state failed").
@@ -337,42 +824,129 @@ func (m *stateManager) broadcast(s State) State {
 		return s
 	}
 
-	m.broadcastChannel.Call("postMessage", map[string]any{
-		"StoreID": m.broadcastStoreID,
-		"State":   s.name,
-		"Value":   string(b),
-	})
+	message := broadcastMessage{
+		StoreID:   m.storeID(),
+		State:     s.name,
+		Value:     string(b),
+		Timestamp: m.versions[s.name].Timestamp,
+	}
+
+	switch m.configs.getBroadcastMode() {
+	case StorageEventMode:
+		// The key is removed right after being set: other tabs are notified of
+		// both changes and ignore the removal.
+		key := broadcastStorageKeyPrefix + s.name
+		if err := s.ctx.LocalStorage().Set(key, message); err != nil {
+			Log(errors.New("broadcasting state through local storage failed").
+				WithTag("state", s.name).
+				Wrap(err))
+		}
+		s.ctx.LocalStorage().Del(key)
+
+	default:
+		if m.broadcastChannel == nil {
+			Log(errors.New("broadcast not supported").
+				WithTag("state", s.name))
+			return s
+		}
+
+		m.broadcastChannel.Call("postMessage", map[string]any{
+			"StoreID":   message.StoreID,
+			"State":     message.State,
+			"Value":     message.Value,
+			"Timestamp": message.Timestamp,
+		})
+	}
 	return s
 }
 
+// storeID returns the ID that identifies the state manager in the versions of
+// the states and in the broadcast messages. It is generated when the state
+// manager is created without one. The mutex must be held by the caller.
+func (m *stateManager) storeID() string {
+	if m.broadcastStoreID == "" {
+		m.broadcastStoreID = uuid.NewString()
+	}
+	return m.broadcastStoreID
+}
+
 func (m *stateManager) initBroadcast(ctx Context) {
 	m.initBroadcastOnce.Do(func() {
+		if m.configs.getBroadcastMode() == StorageEventMode {
+			handleStorageEvent := FuncOf(func(this Value, args []Value) any {
+				event := args[0]
+				key := event.Get("key")
+				if !key.Truthy() || !strings.HasPrefix(key.String(), broadcastStorageKeyPrefix) {
+					return nil
+				}
+
+				newValue := event.Get("newValue")
+				if !newValue.Truthy() {
+					return nil
+				}
+
+				var message broadcastMessage
+				if err := json.Unmarshal([]byte(newValue.String()), &message); err != nil {
+					Log(errors.New("decoding broadcast storage event failed").
+						WithTag("key", key.String()).
+						Wrap(err))
+					return nil
+				}
+				ctx.dispatch(func() {
+					m.handleBroadcast(ctx, message)
+				})
+				return nil
+			})
+			Window().addEventListener("storage", handleStorageEvent, nil)
+			return
+		}
+
 		broadcastChannel := Window().Get("BroadcastChannel")
 		if !broadcastChannel.Truthy() {
 			return
 		}
 		broadcastChannel = broadcastChannel.New("go-app-broadcast-states")
 		m.broadcastChannel = broadcastChannel
-		m.broadcastStoreID = uuid.NewString()
 
 		handleBroadcast := FuncOf(func(this Value, args []Value) any {
-			m.handleBroadcast(ctx, args[0].Get("data"))
+			data := args[0].Get("data")
+			message := broadcastMessage{
+				StoreID:   data.Get("StoreID").String(),
+				State:     data.Get("State").String(),
+				Value:     data.Get("Value").String(),
+				Timestamp: int64(data.Get("Timestamp").Float()),
+			}
+			ctx.dispatch(func() {
+				m.handleBroadcast(ctx, message)
+			})
 			return nil
 		})
 		broadcastChannel.Set("onmessage", handleBroadcast)
 	})
 }
 
-func (m *stateManager) handleBroadcast(ctx Context, data Value) {
-	if storeID := data.Get("StoreID").String(); storeID == "" || storeID == m.broadcastStoreID {
-		return
+// handleBroadcast stores a state value received from another browser tab and
+// notifies its observers. It must be called on the UI goroutine since it
+// recomputes the derived states.
+func (m *stateManager) handleBroadcast(ctx Context, message broadcastMessage) {
+	state := message.State
+	version := stateVersion{
+		Timestamp: message.Timestamp,
+		StoreID:   message.StoreID,
 	}
 
 	m.mutex.Lock()
-	defer m.mutex.Unlock()
+	if message.StoreID == "" || message.StoreID == m.storeID() || !m.versions[state].before(version) {
+		m.mutex.Unlock()
+		return
+	}
+	m.setVersion(state, version)
 
-	state := data.Get("State").String()
-	value := []byte(data.Get("Value").String())
+	if m.states == nil {
+		m.states = make(map[string]State)
+	}
+	value := []byte(message.Value)
+	m.states[state] = State{value: broadcastValue(value)}
 
 	for _, observer := range m.observers[state] {
 		o := observer
@@ -402,16 +976,151 @@ func (m *stateManager) handleBroadcast(ctx Context, data Value) {
 			}
 		})
 	}
+	m.mutex.Unlock()
+
+	m.invalidateDependents(ctx, state)
+}
+
+func (m *stateManager) setVersion(state string, v stateVersion) {
+	if m.versions == nil {
+		m.versions = make(map[string]stateVersion)
+	}
+	m.versions[state] = v
 }
 
 // Delete removes the specified state from the managed states and also deletes
-// it from the local storage if it was previously persisted.
+// it from its storage if it was previously persisted.
 func (m *stateManager) Delete(ctx Context, state string) {
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
+	before, tracked := m.historySnapshot(state)
+	if tracked && before.set {
+		m.trackChange(stateChange{
+			state:  state,
+			before: before,
+		})
+	}
 
+	m.mutex.Lock()
 	delete(m.states, state)
-	ctx.LocalStorage().Del(state)
+	m.removeDerivedState(state)
+	m.configs.storage(ctx, state).Del(state)
+	m.mutex.Unlock()
+
+	m.debug.Record(ctx, DebugStateDelete, state, nil, nil)
+	m.invalidateDependents(ctx, state)
+}
+
+// Derive declares a state computed from the given dependencies. The state is
+// lazily computed when it is read, or when one of its dependencies changes
+// while it is observed.
+func (m *stateManager) Derive(ctx Context, state string, compute func(Context) any, dependencies ...string) {
+	m.mutex.Lock()
+	if m.derivedStates == nil {
+		m.derivedStates = make(map[string]*derivedState)
+	}
+	if m.dependents == nil {
+		m.dependents = make(map[string]map[string]struct{})
+	}
+
+	m.removeDerivedState(state)
+	m.derivedStates[state] = &derivedState{
+		compute:      compute,
+		dependencies: dependencies,
+		dirty:        true,
+	}
+	for _, dependency := range dependencies {
+		dependents := m.dependents[dependency]
+		if dependents == nil {
+			dependents = make(map[string]struct{})
+			m.dependents[dependency] = dependents
+		}
+		dependents[state] = struct{}{}
+	}
+	observed := len(m.observers[state]) != 0
+	m.mutex.Unlock()
+
+	if observed {
+		m.computeDerivedState(ctx, state)
+		return
+	}
+	m.invalidateDependents(ctx, state)
+}
+
+func (m *stateManager) removeDerivedState(state string) {
+	derived, ok := m.derivedStates[state]
+	if !ok {
+		return
+	}
+
+	for _, dependency := range derived.dependencies {
+		delete(m.dependents[dependency], state)
+		if len(m.dependents[dependency]) == 0 {
+			delete(m.dependents, dependency)
+		}
+	}
+	delete(m.derivedStates, state)
+}
+
+func (m *stateManager) dirtyDerivedState(state string) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	derived, ok := m.derivedStates[state]
+	return ok && derived.dirty && !derived.computing
+}
+
+// invalidateDependents marks the states that are directly or indirectly
+// derived from the given state as dirty and recomputes the ones that are
+// observed.
+func (m *stateManager) invalidateDependents(ctx Context, state string) {
+	m.mutex.Lock()
+	var observed []string
+	var invalidate func(string)
+	invalidate = func(state string) {
+		for dependent := range m.dependents[state] {
+			derived := m.derivedStates[dependent]
+			if derived.computing || derived.dirty {
+				continue
+			}
+
+			derived.dirty = true
+			if len(m.observers[dependent]) != 0 {
+				observed = append(observed, dependent)
+			}
+			invalidate(dependent)
+		}
+	}
+	invalidate(state)
+	m.mutex.Unlock()
+
+	for _, dependent := range observed {
+		m.computeDerivedState(ctx, dependent)
+	}
+}
+
+func (m *stateManager) computeDerivedState(ctx Context, state string) {
+	m.mutex.Lock()
+	derived, ok := m.derivedStates[state]
+	if !ok || derived.computing {
+		m.mutex.Unlock()
+		return
+	}
+	derived.computing = true
+	m.mutex.Unlock()
+
+	defer func() {
+		m.mutex.Lock()
+		derived.computing = false
+		m.mutex.Unlock()
+	}()
+
+	value := derived.compute(ctx)
+
+	m.mutex.Lock()
+	derived.dirty = false
+	m.mutex.Unlock()
+
+	m.set(ctx, state, value)
+	m.invalidateDependents(ctx, state)
 }
 
 // Cleanup removes observers that are no longer active and cleans up any states
@@ -433,18 +1142,20 @@ func (m *stateManager) Cleanup() {
 	}
 }
 
-// CleanupExpiredPersistedStates traverses the local storage to identify and
+// CleanupExpiredPersistedStates traverses the state storages to identify and
 // remove any persisted states that have expired. This method ensures that the
-// local storage is kept clean by eliminating outdated or irrelevant state data.
+// storages are kept clean by eliminating outdated or irrelevant state data.
 func (m *stateManager) CleanupExpiredPersistedStates(ctx Context) {
-	ctx.LocalStorage().ForEach(func(key string) {
-		var state storableState
-		ctx.LocalStorage().Get(key, &state)
-		if (len(state.Value) != 0 || len(state.EncryptedValue) != 0) &&
-			expiredTime(state.ExpiresAt) {
-			ctx.LocalStorage().Del(key)
-		}
-	})
+	for _, storage := range m.configs.storages(ctx) {
+		storage.ForEach(func(key string) {
+			var state storableState
+			storage.Get(key, &state)
+			if (len(state.Value) != 0 || len(state.EncryptedValue) != 0) &&
+				expiredTime(state.ExpiresAt) {
+				storage.Del(key)
+			}
+		})
+	}
 }
 
 func storeValue(recv, v any) error {
@@ -460,7 +1171,7 @@ func storeValue(recv, v any) error {
 		dst.Set(reflect.Zero(dst.Type()))
 		return nil
 
-	case src.Kind() == reflect.Ptr:
+	case src.Kind() == reflect.Ptr && src.Type() != dst.Type():
 		src = src.Elem()
 	}
 
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 	"time"
@@ -306,6 +307,17 @@ func TestStateManagerGet(t *testing.T) {
 }
 
 func TestStateManagerSet(t *testing.T) {
+	t.Run("state version is set with the store id before broadcast", func(t *testing.T) {
+		stateName := uuid.NewString()
+
+		e := newTestEngine()
+		storeID := e.states.broadcastStoreID
+		require.NotEmpty(t, storeID)
+
+		e.states.Set(e.baseContext(), stateName, 42)
+		require.Equal(t, storeID, e.states.versions[stateName].StoreID)
+	})
+
 	t.Run("state is set", func(t *testing.T) {
 		stateName := uuid.NewString()
 
@@ -667,6 +679,89 @@ func TestStateManagerSet(t *testing.T) {
 	})
 }
 
+func TestStateManagerHandleBroadcast(t *testing.T) {
+	stateName := uuid.NewString()
+
+	var m stateManager
+	ctx := makeTestContext()
+	m.initBroadcast(ctx)
+	m.Set(ctx, stateName, 42)
+	local := m.versions[stateName]
+	require.NotZero(t, local.Timestamp)
+
+	t.Run("message from the same store is ignored", func(t *testing.T) {
+		m.handleBroadcast(ctx, broadcastMessage{
+			StoreID:   m.broadcastStoreID,
+			State:     stateName,
+			Value:     "21",
+			Timestamp: local.Timestamp + 1,
+		})
+
+		var v int
+		m.Get(ctx, stateName, &v)
+		require.Equal(t, 42, v)
+	})
+
+	t.Run("older message is ignored", func(t *testing.T) {
+		m.handleBroadcast(ctx, broadcastMessage{
+			StoreID:   uuid.NewString(),
+			State:     stateName,
+			Value:     "21",
+			Timestamp: local.Timestamp - 1,
+		})
+
+		var v int
+		m.Get(ctx, stateName, &v)
+		require.Equal(t, 42, v)
+		require.Equal(t, local, m.versions[stateName])
+	})
+
+	t.Run("newer message wins", func(t *testing.T) {
+		storeID := uuid.NewString()
+		m.handleBroadcast(ctx, broadcastMessage{
+			StoreID:   storeID,
+			State:     stateName,
+			Value:     "84",
+			Timestamp: local.Timestamp + 1,
+		})
+
+		var v int
+		m.Get(ctx, stateName, &v)
+		require.Equal(t, 84, v)
+		require.Equal(t, stateVersion{
+			Timestamp: local.Timestamp + 1,
+			StoreID:   storeID,
+		}, m.versions[stateName])
+	})
+
+	t.Run("local change after a broadcast wins", func(t *testing.T) {
+		m.Set(ctx, stateName, 168)
+		require.True(t, local.before(m.versions[stateName]))
+
+		var v int
+		m.Get(ctx, stateName, &v)
+		require.Equal(t, 168, v)
+	})
+}
+
+func TestStateVersionBefore(t *testing.T) {
+	require.True(t, stateVersion{Timestamp: 1}.before(stateVersion{Timestamp: 2}))
+	require.False(t, stateVersion{Timestamp: 2}.before(stateVersion{Timestamp: 1}))
+	require.True(t, stateVersion{Timestamp: 1, StoreID: "a"}.before(stateVersion{Timestamp: 1, StoreID: "b"}))
+	require.False(t, stateVersion{Timestamp: 1, StoreID: "a"}.before(stateVersion{Timestamp: 1, StoreID: "a"}))
+}
+
+func TestSetBroadcastMode(t *testing.T) {
+	defer SetBroadcastMode(BroadcastChannelMode)
+
+	require.Equal(t, BroadcastChannelMode, stateConfigs.getBroadcastMode())
+	SetBroadcastMode(StorageEventMode)
+	require.Equal(t, StorageEventMode, stateConfigs.getBroadcastMode())
+
+	var registry *stateConfigRegistry
+	require.Equal(t, BroadcastChannelMode, registry.getBroadcastMode())
+}
+
 func TestStateManagerDelete(t *testing.T) {
 	t.Run("state is deleted from memory", func(t *testing.T) {
 		stateName := uuid.NewString()
@@ -701,6 +796,434 @@ func TestStateManagerDelete(t *testing.T) {
 	})
 }
 
+func TestStateManagerDerive(t *testing.T) {
+	double := func(dependency string, computes *int) func(Context) any {
+		return func(ctx Context) any {
+			*computes++
+			var v int
+			ctx.GetState(dependency, &v)
+			return v * 2
+		}
+	}
+
+	t.Run("derived state is computed lazily", func(t *testing.T) {
+		stateName := uuid.NewString()
+		derivedName := uuid.NewString()
+
+		e := newTestEngine()
+		ctx := e.baseContext()
+
+		var computes int
+		e.states.Set(ctx, stateName, 21)
+		e.states.Derive(ctx, derivedName, double(stateName, &computes), stateName)
+		require.Zero(t, computes)
+
+		var v int
+		ctx.GetState(derivedName, &v)
+		require.Equal(t, 42, v)
+		require.Equal(t, 1, computes)
+
+		ctx.GetState(derivedName, &v)
+		require.Equal(t, 1, computes)
+
+		e.states.Set(ctx, stateName, 4)
+		require.Equal(t, 1, computes)
+
+		ctx.GetState(derivedName, &v)
+		require.Equal(t, 8, v)
+		require.Equal(t, 2, computes)
+	})
+
+	t.Run("observed derived state is recomputed and notified", func(t *testing.T) {
+		stateName := uuid.NewString()
+		derivedName := uuid.NewString()
+
+		e := newTestEngine()
+		ctx := e.baseContext()
+
+		var nm nodeManager
+		compo, err := nm.Mount(ctx, 1, &hello{})
+		require.NoError(t, err)
+		ctx = nm.context(ctx, compo)
+
+		var computes int
+		var changes int
+		var v int
+		e.states.Derive(ctx, derivedName, double(stateName, &computes), stateName)
+		e.states.Observe(ctx, derivedName, &v).OnChange(func() {
+			changes++
+		})
+		e.ConsumeAll()
+		require.Equal(t, 1, computes)
+
+		e.states.Set(ctx, stateName, 21)
+		e.ConsumeAll()
+		require.Equal(t, 2, computes)
+		require.Equal(t, 42, v)
+		require.Equal(t, 1, changes)
+	})
+
+	t.Run("state derived from a derived state is computed", func(t *testing.T) {
+		stateName := uuid.NewString()
+		derivedName := uuid.NewString()
+		derivedDerivedName := uuid.NewString()
+
+		e := newTestEngine()
+		ctx := e.baseContext()
+
+		var computes int
+		e.states.Set(ctx, stateName, 1)
+		e.states.Derive(ctx, derivedName, double(stateName, &computes), stateName)
+		e.states.Derive(ctx, derivedDerivedName, double(derivedName, &computes), derivedName)
+
+		var v int
+		ctx.GetState(derivedDerivedName, &v)
+		require.Equal(t, 4, v)
+
+		e.states.Set(ctx, stateName, 2)
+		ctx.GetState(derivedDerivedName, &v)
+		require.Equal(t, 8, v)
+	})
+
+	t.Run("derived state is computed again after a panic", func(t *testing.T) {
+		stateName := uuid.NewString()
+		derivedName := uuid.NewString()
+
+		e := newTestEngine()
+		ctx := e.baseContext()
+
+		var computes int
+		e.states.Set(ctx, stateName, 0)
+		e.states.Derive(ctx, derivedName, func(ctx Context) any {
+			var v int
+			ctx.GetState(stateName, &v)
+			if v == 0 {
+				panic("no value")
+			}
+			return double(stateName, &computes)(ctx)
+		}, stateName)
+
+		var v int
+		require.Panics(t, func() {
+			ctx.GetState(derivedName, &v)
+		})
+
+		e.states.Set(ctx, stateName, 21)
+		ctx.GetState(derivedName, &v)
+		require.Equal(t, 42, v)
+		require.Equal(t, 1, computes)
+	})
+
+	t.Run("cyclic derived states do not recompute infinitely", func(t *testing.T) {
+		stateA := uuid.NewString()
+		stateB := uuid.NewString()
+
+		e := newTestEngine()
+		ctx := e.baseContext()
+
+		var nm nodeManager
+		compo, err := nm.Mount(ctx, 1, &hello{})
+		require.NoError(t, err)
+		ctx = nm.context(ctx, compo)
+
+		var computes int
+		var a, b int
+		e.states.Derive(ctx, stateA, double(stateB, &computes), stateB)
+		e.states.Derive(ctx, stateB, double(stateA, &computes), stateA)
+		e.states.Observe(ctx, stateA, &a)
+		e.states.Observe(ctx, stateB, &b)
+		e.ConsumeAll()
+		require.Less(t, computes, 10)
+	})
+
+	t.Run("deleted derived state is no longer computed", func(t *testing.T) {
+		stateName := uuid.NewString()
+		derivedName := uuid.NewString()
+
+		e := newTestEngine()
+		ctx := e.baseContext()
+
+		var computes int
+		e.states.Derive(ctx, derivedName, double(stateName, &computes), stateName)
+		e.states.Delete(ctx, derivedName)
+		require.Empty(t, e.states.derivedStates)
+		require.Empty(t, e.states.dependents)
+
+		var v int
+		ctx.GetState(derivedName, &v)
+		require.Zero(t, computes)
+	})
+}
+
+func TestStateManagerStorage(t *testing.T) {
+	t.Run("state is persisted into the configured storage", func(t *testing.T) {
+		stateName := uuid.NewString()
+		ctx := makeTestContext()
+
+		m := stateManager{configs: &stateConfigRegistry{}}
+		m.configs.set(StateConfig{
+			name:    stateName,
+			storage: SessionStateStorage,
+		})
+
+		m.Set(ctx, stateName, 42).Persist()
+		require.True(t, ctx.SessionStorage().Contains(stateName))
+		require.False(t, ctx.LocalStorage().Contains(stateName))
+
+		delete(m.states, stateName)
+		var number int
+		m.Get(ctx, stateName, &number)
+		require.Equal(t, 42, number)
+
+		m.Delete(ctx, stateName)
+		require.False(t, ctx.SessionStorage().Contains(stateName))
+	})
+
+	t.Run("state is persisted into the default storage", func(t *testing.T) {
+		stateName := uuid.NewString()
+		ctx := makeTestContext()
+		storage := NewMemoryStorage()
+
+		m := stateManager{configs: &stateConfigRegistry{}}
+		m.configs.setDefaultStorage(func(Context) BrowserStorage {
+			return storage
+		})
+
+		m.Set(ctx, stateName, 42).Persist()
+		require.True(t, storage.Contains(stateName))
+		require.False(t, ctx.LocalStorage().Contains(stateName))
+	})
+
+	t.Run("state is persisted into the local storage when not configured", func(t *testing.T) {
+		stateName := uuid.NewString()
+		ctx := makeTestContext()
+
+		var m stateManager
+		m.Set(ctx, stateName, 42).Persist()
+		require.True(t, ctx.LocalStorage().Contains(stateName))
+		ctx.LocalStorage().Del(stateName)
+	})
+}
+
+func TestStateManagerMigrations(t *testing.T) {
+	type cart struct {
+		Items []string
+	}
+
+	newManager := func(stateName string) *stateManager {
+		m := &stateManager{configs: &stateConfigRegistry{}}
+		m.configs.set(StateConfig{
+			name:    stateName,
+			version: 2,
+			migrations: map[int]StateMigration{
+				0: func(v json.RawMessage) (json.RawMessage, error) {
+					var item string
+					if err := json.Unmarshal(v, &item); err != nil {
+						return nil, err
+					}
+					return json.Marshal([]string{item})
+				},
+				1: func(v json.RawMessage) (json.RawMessage, error) {
+					var items []string
+					if err := json.Unmarshal(v, &items); err != nil {
+						return nil, err
+					}
+					return json.Marshal(cart{Items: items})
+				},
+			},
+		})
+		return m
+	}
+
+	t.Run("persisted state is stored with its version", func(t *testing.T) {
+		stateName := uuid.NewString()
+		ctx := makeTestContext()
+		defer ctx.LocalStorage().Del(stateName)
+
+		m := newManager(stateName)
+		m.Set(ctx, stateName, cart{Items: []string{"foo"}}).Persist()
+
+		var value storableState
+		err := ctx.LocalStorage().Get(stateName, &value)
+		require.NoError(t, err)
+		require.Equal(t, 2, value.Version)
+	})
+
+	t.Run("persisted state with an older version is migrated", func(t *testing.T) {
+		stateName := uuid.NewString()
+		ctx := makeTestContext()
+		defer ctx.LocalStorage().Del(stateName)
+
+		err := ctx.LocalStorage().Set(stateName, storableState{
+			Value: json.RawMessage(`"foo"`),
+		})
+		require.NoError(t, err)
+
+		m := newManager(stateName)
+		var c cart
+		m.Get(ctx, stateName, &c)
+		require.Equal(t, []string{"foo"}, c.Items)
+
+		var value storableState
+		err = ctx.LocalStorage().Get(stateName, &value)
+		require.NoError(t, err)
+		require.Equal(t, 2, value.Version)
+		require.JSONEq(t, `{"Items":["foo"]}`, string(value.Value))
+	})
+
+	t.Run("encrypted persisted state with an older version is migrated", func(t *testing.T) {
+		stateName := uuid.NewString()
+		ctx := makeTestContext()
+		defer ctx.LocalStorage().Del(stateName)
+
+		m := newManager(stateName)
+		m.configs.set(StateConfig{name: "legacy"})
+		m.Set(ctx, "legacy", []string{"foo", "bar"}).PersistWithEncryption()
+		defer ctx.LocalStorage().Del("legacy")
+
+		var value storableState
+		err := ctx.LocalStorage().Get("legacy", &value)
+		require.NoError(t, err)
+		value.Version = 1
+		err = ctx.LocalStorage().Set(stateName, value)
+		require.NoError(t, err)
+
+		var c cart
+		m.Get(ctx, stateName, &c)
+		require.Equal(t, []string{"foo", "bar"}, c.Items)
+
+		var migrated storableState
+		err = ctx.LocalStorage().Get(stateName, &migrated)
+		require.NoError(t, err)
+		require.Equal(t, 2, migrated.Version)
+		require.NotEmpty(t, migrated.EncryptedValue)
+	})
+
+	t.Run("persisted state with a missing migration falls back to default value", func(t *testing.T) {
+		stateName := uuid.NewString()
+		ctx := makeTestContext()
+		defer ctx.LocalStorage().Del(stateName)
+
+		err := ctx.LocalStorage().Set(stateName, storableState{
+			Value:   json.RawMessage(`"foo"`),
+			Version: -1,
+		})
+		require.NoError(t, err)
+
+		m := newManager(stateName)
+		config := m.configs.get(stateName)
+		config.defaultValue = cart{Items: []string{"default"}}
+		m.configs.set(config)
+
+		var c cart
+		m.Get(ctx, stateName, &c)
+		require.Equal(t, []string{"default"}, c.Items)
+		require.False(t, ctx.LocalStorage().Contains(stateName))
+	})
+
+	t.Run("persisted state with a newer version falls back to default value", func(t *testing.T) {
+		stateName := uuid.NewString()
+		ctx := makeTestContext()
+		defer ctx.LocalStorage().Del(stateName)
+
+		err := ctx.LocalStorage().Set(stateName, storableState{
+			Value:   json.RawMessage(`{"Items":["foo"]}`),
+			Version: 3,
+		})
+		require.NoError(t, err)
+
+		m := newManager(stateName)
+		config := m.configs.get(stateName)
+		config.defaultValue = cart{}
+		m.configs.set(config)
+
+		c := cart{Items: []string{"bar"}}
+		m.Get(ctx, stateName, &c)
+		require.Empty(t, c.Items)
+	})
+
+	t.Run("undecodable persisted state without default value is kept", func(t *testing.T) {
+		stateName := uuid.NewString()
+		ctx := makeTestContext()
+		defer ctx.LocalStorage().Del(stateName)
+
+		err := ctx.LocalStorage().Set(stateName, storableState{
+			Value:   json.RawMessage(`42`),
+			Version: 2,
+		})
+		require.NoError(t, err)
+
+		m := newManager(stateName)
+		var c cart
+		m.Get(ctx, stateName, &c)
+		require.Empty(t, c.Items)
+		require.True(t, ctx.LocalStorage().Contains(stateName))
+	})
+
+	t.Run("undecryptable persisted state with default value is kept", func(t *testing.T) {
+		stateName := uuid.NewString()
+		ctx := makeTestContext()
+		defer ctx.LocalStorage().Del(stateName)
+
+		err := ctx.LocalStorage().Set(stateName, storableState{
+			EncryptedValue: []byte("encrypted with another key"),
+			Version:        2,
+		})
+		require.NoError(t, err)
+
+		m := newManager(stateName)
+		config := m.configs.get(stateName)
+		config.defaultValue = cart{Items: []string{"default"}}
+		m.configs.set(config)
+
+		var c cart
+		m.Get(ctx, stateName, &c)
+		require.Equal(t, []string{"default"}, c.Items)
+		require.True(t, ctx.LocalStorage().Contains(stateName))
+	})
+
+	t.Run("state that is not set gets default value", func(t *testing.T) {
+		stateName := uuid.NewString()
+		ctx := makeTestContext()
+
+		m := newManager(stateName)
+		config := m.configs.get(stateName)
+		config.defaultValue = cart{Items: []string{"default"}}
+		m.configs.set(config)
+
+		var c cart
+		m.Get(ctx, stateName, &c)
+		require.Equal(t, []string{"default"}, c.Items)
+	})
+}
+
+func TestConfigureState(t *testing.T) {
+	stateName := uuid.NewString()
+	defer delete(stateConfigs.configs, stateName)
+
+	config := ConfigureState(stateName)
+	require.Equal(t, stateName, config.name)
+	require.Nil(t, config.storage)
+
+	ConfigureState(stateName).StoreIn(CookieStateStorage)
+	config = ConfigureState(stateName)
+	require.NotNil(t, config.storage)
+
+	ConfigureState(stateName).
+		Version(2).
+		Migrate(0, func(v json.RawMessage) (json.RawMessage, error) { return v, nil }).
+		Migrate(1, func(v json.RawMessage) (json.RawMessage, error) { return v, nil }).
+		Default(42)
+	config = ConfigureState(stateName)
+	require.NotNil(t, config.storage)
+	require.Equal(t, 2, config.version)
+	require.Len(t, config.migrations, 2)
+	require.Equal(t, 42, config.defaultValue)
+
+	ctx := makeTestContext()
+	require.Equal(t, ctx.Cookies(), stateConfigs.storage(ctx, stateName))
+}
+
 func TestStateManagerCleanup(t *testing.T) {
 	t.Run("non observing observers are removed", func(t *testing.T) {
 		stateName := uuid.NewString()
@@ -782,6 +1305,60 @@ func TestStateManagerCleanupExpiredPersistedStates(t *testing.T) {
 	})
 }
 
+func TestStateKey(t *testing.T) {
+	type cart struct {
+		Items []string
+	}
+
+	e := newTestEngine()
+	hello := &hello{}
+	e.Load(hello)
+	ctx := e.nodes.context(e.baseContext(), hello)
+
+	key := NewStateKey[cart](uuid.NewString())
+	require.NotEmpty(t, key.Name())
+	require.Zero(t, key.Get(ctx))
+
+	key.Set(ctx, cart{Items: []string{"foo"}})
+	require.Equal(t, []string{"foo"}, key.Get(ctx).Items)
+
+	var c cart
+	key.Observe(ctx, &c)
+	require.Equal(t, []string{"foo"}, c.Items)
+
+	key.Set(ctx, cart{Items: []string{"foo", "bar"}})
+	e.ConsumeAll()
+	require.Equal(t, []string{"foo", "bar"}, c.Items)
+
+	count := NewStateKey[int](uuid.NewString())
+	count.Derive(ctx, func(ctx Context) int {
+		return len(key.Get(ctx).Items)
+	}, key.Name())
+	require.Equal(t, 2, count.Get(ctx))
+
+	key.Del(ctx)
+	require.Zero(t, key.Get(ctx))
+	require.Zero(t, count.Get(ctx))
+
+	require.Equal(t, key.Name(), key.Configure().name)
+}
+
+func TestStateKeyPointer(t *testing.T) {
+	type user struct {
+		Name string
+	}
+
+	e := newTestEngine()
+	ctx := e.baseContext()
+
+	key := NewStateKey[*user](uuid.NewString())
+	require.Nil(t, key.Get(ctx))
+
+	u := &user{Name: "Maxence"}
+	key.Set(ctx, u)
+	require.Equal(t, u, key.Get(ctx))
+}
+
 func TestStoreValue(t *testing.T) {
 	nb := 42
 	c := copyTester{pointer: &nb}
@@ -817,6 +1394,12 @@ func TestStoreValue(t *testing.T) {
 			recv:     &c.unexported,
 			expected: 0,
 		},
+		{
+			scenario: "pointer to pointer receiver",
+			src:      &nb,
+			recv:     &c.pointer,
+			expected: &nb,
+		},
 		{
 			scenario: "nil to pointer receiver",
 			src:      nil,
@@ -60,38 +60,49 @@ func GenerateStaticWebsite(dir string, h *Handler, pages ...string) error {
 			}
 
 		default:
-			filename := path
-			if filename == "/" {
-				filename = "/index.html"
+			if err := generateStaticFile(dir, server.URL, path); err != nil {
+				return err
 			}
+		}
+	}
 
-			f, err := createStaticFile(dir, filename)
-			if err != nil {
-				return errors.New("creating file failed").
-					WithTag("path", path).
-					WithTag("filename", filename).
-					Wrap(err)
-			}
-			defer f.Close()
-
-			page, err := createStaticPage(server.URL + path)
-			if err != nil {
-				return errors.New("creating page failed").
-					WithTag("path", path).
-					WithTag("filename", filename).
-					Wrap(err)
-			}
+	if err := generateStaticFile(dir, server.URL, componentStylesPath); err != nil {
+		return err
+	}
 
-			if n, err := f.Write(page); err != nil {
-				return errors.New("writing page failed").
-					WithTag("path", path).
-					WithTag("filename", filename).
-					WithTag("bytes-written", n).
-					Wrap(err)
-			}
-		}
+	return nil
+}
+
+func generateStaticFile(dir, serverURL, path string) error {
+	filename := path
+	if filename == "/" {
+		filename = "/index.html"
 	}
 
+	f, err := createStaticFile(dir, filename)
+	if err != nil {
+		return errors.New("creating file failed").
+			WithTag("path", path).
+			WithTag("filename", filename).
+			Wrap(err)
+	}
+	defer f.Close()
+
+	page, err := createStaticPage(serverURL + path)
+	if err != nil {
+		return errors.New("creating page failed").
+			WithTag("path", path).
+			WithTag("filename", filename).
+			Wrap(err)
+	}
+
+	if n, err := f.Write(page); err != nil {
+		return errors.New("writing page failed").
+			WithTag("path", path).
+			WithTag("filename", filename).
+			WithTag("bytes-written", n).
+			Wrap(err)
+	}
 	return nil
 }
 
@@ -38,6 +38,7 @@ func TestGenerateStaticWebsite(t *testing.T) {
 		filepath.Join(dir, "app-worker.js"),
 		filepath.Join(dir, "manifest.webmanifest"),
 		filepath.Join(dir, "app.css"),
+		filepath.Join(dir, "app-components.css"),
 		filepath.Join(dir, "hello.html"),
 		filepath.Join(dir, "world.html"),
 		filepath.Join(dir, "nested", "foo.html"),
@@ -1,8 +1,13 @@
 package app
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/maxence-charriere/go-app/v11/pkg/errors"
 )
@@ -48,6 +53,12 @@ type BrowserStorage interface {
 	Contains(k string) bool
 }
 
+// NewMemoryStorage returns a BrowserStorage that keeps its items in memory. It
+// is useful to inject a storage when testing components that persist states.
+func NewMemoryStorage() BrowserStorage {
+	return newMemoryStorage()
+}
+
 type memoryStorage struct {
 	mu   sync.RWMutex
 	data map[string][]byte
@@ -203,3 +214,269 @@ func (s *jsStorage) Contains(k string) bool {
 	defer s.mutex.Unlock()
 	return !Window().Get(s.name).Call("getItem", k).IsNull()
 }
+
+const (
+	cookieMaxAge = 365 * 24 * time.Hour
+
+	// The prefix of the names of the cookies managed by cookie storages.
+	// Cookies without it, such as the ones set by third parties, are ignored.
+	cookiePrefix = "goapp_"
+
+	// The maximum size of the name and value of a cookie, in bytes.
+	cookieMaxSize = 4096
+)
+
+type jsCookieStorage struct {
+	mutex sync.Mutex
+}
+
+func newJSCookieStorage() *jsCookieStorage {
+	return &jsCookieStorage{}
+}
+
+func (s *jsCookieStorage) Set(k string, v any) error {
+	value, err := encodeCookie(k, v)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.write(k, value, int(cookieMaxAge.Seconds()))
+	return nil
+}
+
+func (s *jsCookieStorage) Get(k string, v any) error {
+	s.mutex.Lock()
+	value, ok := s.cookies()[k]
+	s.mutex.Unlock()
+	if !ok {
+		return nil
+	}
+	return decodeCookieValue(value, v)
+}
+
+func (s *jsCookieStorage) Del(k string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.write(k, "", -1)
+}
+
+func (s *jsCookieStorage) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.cookies())
+}
+
+func (s *jsCookieStorage) Clear() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for k := range s.cookies() {
+		s.write(k, "", -1)
+	}
+}
+
+func (s *jsCookieStorage) ForEach(f func(k string)) {
+	s.mutex.Lock()
+	cookies := s.cookies()
+	s.mutex.Unlock()
+
+	for k := range cookies {
+		f(k)
+	}
+}
+
+func (s *jsCookieStorage) Contains(k string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, ok := s.cookies()[k]
+	return ok
+}
+
+func (s *jsCookieStorage) cookies() map[string]string {
+	cookies := make(map[string]string)
+	for _, cookie := range strings.Split(Window().Get("document").Get("cookie").String(), ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(cookie), "=")
+		if k, ok := cookieKey(name); ok {
+			cookies[k] = value
+		}
+	}
+	return cookies
+}
+
+func (s *jsCookieStorage) write(k, value string, maxAge int) {
+	cookie := http.Cookie{
+		Name:     cookieName(k),
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		SameSite: http.SameSiteLaxMode,
+		Secure:   Window().URL().Scheme == "https",
+	}
+	Window().Get("document").Set("cookie", cookie.String())
+}
+
+// httpCookieStorage is a BrowserStorage that reads the cookies of an HTTP
+// request and records the changes to send back with its response.
+type httpCookieStorage struct {
+	mutex   sync.Mutex
+	cookies map[string]string
+	changes map[string]*http.Cookie
+}
+
+func newHTTPCookieStorage(r *http.Request) *httpCookieStorage {
+	s := &httpCookieStorage{
+		cookies: make(map[string]string),
+		changes: make(map[string]*http.Cookie),
+	}
+
+	if r != nil {
+		for _, cookie := range r.Cookies() {
+			if k, ok := cookieKey(cookie.Name); ok {
+				s.cookies[k] = cookie.Value
+			}
+		}
+	}
+	return s
+}
+
+func (s *httpCookieStorage) Set(k string, v any) error {
+	value, err := encodeCookie(k, v)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.cookies[k] = value
+	s.changes[k] = &http.Cookie{
+		Name:     cookieName(k),
+		Value:    value,
+		Path:     "/",
+		MaxAge:   int(cookieMaxAge.Seconds()),
+		SameSite: http.SameSiteLaxMode,
+	}
+	return nil
+}
+
+func (s *httpCookieStorage) Get(k string, v any) error {
+	s.mutex.Lock()
+	value, ok := s.cookies[k]
+	s.mutex.Unlock()
+	if !ok {
+		return nil
+	}
+	return decodeCookieValue(value, v)
+}
+
+func (s *httpCookieStorage) Del(k string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.del(k)
+}
+
+func (s *httpCookieStorage) del(k string) {
+	delete(s.cookies, k)
+	s.changes[k] = &http.Cookie{
+		Name:   cookieName(k),
+		Path:   "/",
+		MaxAge: -1,
+	}
+}
+
+func (s *httpCookieStorage) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.cookies)
+}
+
+func (s *httpCookieStorage) Clear() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for k := range s.cookies {
+		s.del(k)
+	}
+}
+
+func (s *httpCookieStorage) ForEach(f func(k string)) {
+	s.mutex.Lock()
+	keys := make([]string, 0, len(s.cookies))
+	for k := range s.cookies {
+		keys = append(keys, k)
+	}
+	s.mutex.Unlock()
+
+	for _, k := range keys {
+		f(k)
+	}
+}
+
+func (s *httpCookieStorage) Contains(k string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, ok := s.cookies[k]
+	return ok
+}
+
+// setResponseCookies sets the cookies that were modified into the given
+// response header.
+func (s *httpCookieStorage) setResponseCookies(w http.ResponseWriter) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, cookie := range s.changes {
+		http.SetCookie(w, cookie)
+	}
+}
+
+// cookieName returns the name of the cookie that stores the given key.
+func cookieName(k string) string {
+	return cookiePrefix + url.QueryEscape(k)
+}
+
+// cookieKey returns the key stored by the cookie with the given name. It
+// reports false when the cookie is not managed by a cookie storage.
+func cookieKey(name string) (string, bool) {
+	name, ok := strings.CutPrefix(name, cookiePrefix)
+	if !ok {
+		return "", false
+	}
+
+	k, err := url.QueryUnescape(name)
+	if err != nil {
+		return "", false
+	}
+	return k, true
+}
+
+// encodeCookie returns the cookie value that stores the given value. It
+// returns an error when the cookie exceeds the size browsers accept.
+func encodeCookie(k string, v any) (string, error) {
+	value, err := encodeCookieValue(v)
+	if err != nil {
+		return "", err
+	}
+
+	if size := len(cookieName(k)) + len(value); size > cookieMaxSize {
+		return "", errors.New("cookie is too large").
+			WithTag("key", k).
+			WithTag("size", size).
+			WithTag("max-size", cookieMaxSize)
+	}
+	return value, nil
+}
+
+func encodeCookieValue(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeCookieValue(value string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return errors.New("decoding cookie value failed").Wrap(err)
+	}
+	return json.Unmarshal(b, v)
+}
@@ -1,6 +1,9 @@
 package app
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -20,6 +23,58 @@ func TestJSSessionStorage(t *testing.T) {
 	testBrowserStorage(t, newJSStorage("sessionStorage"))
 }
 
+func TestJSCookieStorage(t *testing.T) {
+	testSkipNonWasm(t)
+	testBrowserStorage(t, newJSCookieStorage())
+}
+
+func TestHTTPCookieStorage(t *testing.T) {
+	testBrowserStorage(t, newHTTPCookieStorage(nil))
+}
+
+func TestHTTPCookieStorageRequest(t *testing.T) {
+	value, err := encodeCookieValue(obj{Foo: 42, Bar: "hello"})
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: "goapp_%2Fpreferences", Value: value})
+	r.AddCookie(&http.Cookie{Name: "goapp_removed", Value: value})
+	r.AddCookie(&http.Cookie{Name: "_ga", Value: "GA1.1.42"})
+	s := newHTTPCookieStorage(r)
+	require.Equal(t, 2, s.Len())
+	require.False(t, s.Contains("_ga"))
+
+	var o obj
+	err = s.Get("/preferences", &o)
+	require.NoError(t, err)
+	require.Equal(t, 42, o.Foo)
+	require.Equal(t, "hello", o.Bar)
+
+	err = s.Set("/theme", "dark")
+	require.NoError(t, err)
+	s.Del("removed")
+
+	w := httptest.NewRecorder()
+	s.setResponseCookies(w)
+
+	cookies := make(map[string]*http.Cookie)
+	for _, c := range w.Result().Cookies() {
+		cookies[c.Name] = c
+	}
+	require.Len(t, cookies, 2)
+	require.NotEmpty(t, cookies["goapp_%2Ftheme"].Value)
+	require.Greater(t, cookies["goapp_%2Ftheme"].MaxAge, 0)
+	require.Less(t, cookies["goapp_removed"].MaxAge, 0)
+}
+
+func TestHTTPCookieStorageSetTooLarge(t *testing.T) {
+	s := newHTTPCookieStorage(nil)
+
+	err := s.Set("/large", strings.Repeat("a", cookieMaxSize))
+	require.Error(t, err)
+	require.False(t, s.Contains("/large"))
+}
+
 type obj struct {
 	Foo int
 	Bar string
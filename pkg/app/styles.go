@@ -0,0 +1,193 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/maxence-charriere/go-app/v11/pkg/errors"
+)
+
+const (
+	componentStylesPath = "/app-components.css"
+)
+
+var (
+	componentStyles = makeStyleRegistry()
+)
+
+// Styler is the interface that describes a component that declares CSS scoped
+// to itself.
+//
+// The styles are nested within a class generated from the component type and
+// added to the component root, which must be an HTML element. Selectors apply
+// to the elements within the component root, including the ones of its child
+// components, while the & selector refers to the root itself:
+//
+//	func (h *hello) Styles() string {
+//		return `
+//			& { padding: 12px; }
+//			h1 { color: deeppink; }
+//		`
+//	}
+//
+// Scoping relies on native CSS nesting: selectors are not rewritten, and
+// browsers that do not support CSS nesting are not supported.
+//
+// The styles of a component type are collected once. The Handler serves the
+// styles of the routed components in a stylesheet built when it is
+// initialized, and embeds the styles of the other components it rendered in
+// the page head. They are injected into the page head when a component type
+// is rendered for the first time on the client.
+type Styler interface {
+	Composer
+
+	// Styles returns the CSS of the component.
+	Styles() string
+}
+
+type styleRegistry struct {
+	mutex   sync.Mutex
+	classes map[reflect.Type]string
+	types   map[string]reflect.Type
+	styles  map[string]string
+	order   []string
+}
+
+func makeStyleRegistry() styleRegistry {
+	return styleRegistry{
+		classes: make(map[reflect.Type]string),
+		types:   make(map[string]reflect.Type),
+		styles:  make(map[string]string),
+	}
+}
+
+// Register collects the scoped styles of the given component and returns the
+// class that scopes them. Styles are injected into the page head the first
+// time they are collected on the client.
+//
+// A component type whose class is already used by another type is logged as a
+// collision and shares the styles of the first registered type.
+func (r *styleRegistry) Register(v Styler) string {
+	t := reflect.TypeOf(v)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if class, ok := r.classes[t]; ok {
+		return class
+	}
+
+	class := styleScope(v)
+	r.classes[t] = class
+
+	scopedType := t
+	if scopedType.Kind() == reflect.Pointer {
+		scopedType = scopedType.Elem()
+	}
+	if registeredType, ok := r.types[class]; ok {
+		if registeredType != scopedType {
+			Log(errors.New("component style scope collision").
+				WithTag("class", class).
+				WithTag("type", scopedType).
+				WithTag("registered-type", registeredType))
+		}
+		return class
+	}
+	r.types[class] = scopedType
+
+	styles := scopeStyles(class, v.Styles())
+	r.styles[class] = styles
+	r.order = append(r.order, class)
+
+	if IsClient {
+		injectStyles(class, styles)
+	}
+	return class
+}
+
+// Len returns the number of collected styles.
+func (r *styleRegistry) Len() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.order)
+}
+
+// Bundle returns the collected styles, in the order they were collected,
+// starting from the given number of styles collected.
+func (r *styleRegistry) Bundle(from int) []byte {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if from >= len(r.order) {
+		return nil
+	}
+
+	var b bytes.Buffer
+	for _, class := range r.order[from:] {
+		b.WriteString(r.styles[class])
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+// styleScope returns the class that scopes the styles of the given component
+// type. It is the same on the server and on the client.
+func styleScope(v Composer) string {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(t.PkgPath() + "." + t.Name()))
+	return fmt.Sprintf("goapp-%x", h.Sum32())
+}
+
+func scopeStyles(class, styles string) string {
+	return "." + class + " {\n" + strings.TrimSpace(styles) + "\n}"
+}
+
+func injectStyles(class, styles string) {
+	style, err := Window().createElement("style", "")
+	if err != nil {
+		Log(err)
+		return
+	}
+
+	style.setAttr("data-goapp-styles", class)
+	style.Set("textContent", styles)
+	Window().Get("document").Get("head").Call("appendChild", style)
+}
+
+// addStyleScope adds the style scope class of the given component to its root
+// when the component declares scoped styles. It returns an error when the root
+// is not an HTML element.
+func addStyleScope(v Composer, root UI) error {
+	styler, ok := v.(Styler)
+	if !ok {
+		return nil
+	}
+
+	html, ok := root.(HTML)
+	if !ok {
+		return errors.New("component with scoped styles does not render an html element").
+			WithTag("root-type", reflect.TypeOf(root))
+	}
+
+	attrs := html.attrs()
+	if attrs == nil {
+		attrs = make(attributes)
+		html.setAttrs(attrs)
+	}
+
+	class := componentStyles.Register(styler)
+	if !slices.Contains(strings.Fields(attrs["class"]), class) {
+		attrs.Set("class", class)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type styledComponent struct {
+	Compo
+}
+
+func (c *styledComponent) Styles() string {
+	return `
+		& { padding: 12px; }
+		h1 { color: deeppink; }
+	`
+}
+
+func (c *styledComponent) Render() UI {
+	return Div().Class("hello").Body(
+		H1().Text("hello"),
+	)
+}
+
+type styledTextComponent struct {
+	Compo
+}
+
+func (c *styledTextComponent) Styles() string {
+	return "color: red;"
+}
+
+func (c *styledTextComponent) Render() UI {
+	return Text("hello")
+}
+
+func TestStyleScope(t *testing.T) {
+	a := styleScope(&styledComponent{})
+	require.Equal(t, a, styleScope(&styledComponent{}))
+	require.NotEqual(t, a, styleScope(&styledTextComponent{}))
+	require.Regexp(t, "^goapp-[0-9a-f]+$", a)
+}
+
+func TestStyleRegistry(t *testing.T) {
+	r := makeStyleRegistry()
+
+	class := r.Register(&styledComponent{})
+	require.Equal(t, styleScope(&styledComponent{}), class)
+	require.Equal(t, class, r.Register(&styledComponent{}))
+	require.Len(t, r.order, 1)
+
+	bundle := string(r.Bundle(0))
+	require.Contains(t, bundle, "."+class+" {\n")
+	require.Contains(t, bundle, "h1 { color: deeppink; }")
+	require.Empty(t, r.Bundle(r.Len()))
+}
+
+func TestStyleRegistryCollision(t *testing.T) {
+	r := makeStyleRegistry()
+
+	class := styleScope(&styledComponent{})
+	r.types[class] = reflect.TypeOf(styledTextComponent{})
+
+	require.Equal(t, class, r.Register(&styledComponent{}))
+	require.Zero(t, r.Len())
+}
+
+func TestComponentStyleScope(t *testing.T) {
+	t.Run("scope class is added to html root", func(t *testing.T) {
+		class := styleScope(&styledComponent{})
+		html := HTMLString(&styledComponent{})
+		require.Contains(t, html, `class="hello `+class+`"`)
+		require.Contains(t, string(componentStyles.Bundle(0)), "."+class+" {")
+	})
+
+	t.Run("scope class is kept on update", func(t *testing.T) {
+		e := newTestEngine()
+		compo := &styledComponent{}
+		require.NoError(t, e.Load(compo))
+
+		_, err := e.nodes.UpdateComponentRoot(e.baseContext(), compo)
+		require.NoError(t, err)
+		require.Contains(t, compo.root().(HTML).attrs()["class"], styleScope(compo))
+	})
+
+	t.Run("scope class is added once to a reused root", func(t *testing.T) {
+		root := Div().Class("hello")
+		require.NoError(t, addStyleScope(&styledComponent{}, root))
+		require.NoError(t, addStyleScope(&styledComponent{}, root))
+
+		class := root.(HTML).attrs()["class"]
+		require.Equal(t, 1, strings.Count(class, styleScope(&styledComponent{})))
+	})
+
+	t.Run("non html root returns an error", func(t *testing.T) {
+		var m nodeManager
+		_, err := m.Mount(makeTestContext(), 1, &styledTextComponent{})
+		require.Error(t, err)
+	})
+}
+
+func TestHandlerServeComponentStyles(t *testing.T) {
+	Route("/styled-component", NewZeroComponentFactory(&styledComponent{}))
+	defer delete(routes.routes, "/styled-component")
+
+	h := Handler{}
+	h.init()
+	require.Contains(t, h.Styles[1], componentStylesPath+"?v=")
+
+	r := httptest.NewRequest(http.MethodGet, h.Styles[1], nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "text/css", w.Header().Get("Content-Type"))
+	require.Contains(t, w.Body.String(), "."+styleScope(&styledComponent{})+" {")
+}
+
+type styledPageComponent struct {
+	Compo
+}
+
+func (c *styledPageComponent) Styles() string {
+	return "color: teal;"
+}
+
+func (c *styledPageComponent) Render() UI {
+	return Div().Text("styled")
+}
+
+type styledPage struct {
+	Compo
+}
+
+func (p *styledPage) Render() UI {
+	return Main().Body(&styledPageComponent{})
+}
+
+func TestHandlerServePageWithComponentStyles(t *testing.T) {
+	Route("/styled-page", NewZeroComponentFactory(&styledPage{}))
+	defer delete(routes.routes, "/styled-page")
+	componentStyles = makeStyleRegistry()
+
+	h := Handler{}
+	h.init()
+	stylesheet := h.componentStyles
+
+	r := httptest.NewRequest(http.MethodGet, "/styled-page", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	class := styleScope(&styledPageComponent{})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "."+class+" {")
+	require.Equal(t, stylesheet, h.componentStyles)
+	require.NotContains(t, string(h.componentStyles), "."+class+" {")
+}
@@ -0,0 +1,132 @@
+package app
+
+// Suspender describes components that load their content asynchronously, such
+// as from an HTTP request started in OnMount or OnPreRender. It allows the
+// closest enclosing Suspense element to display a fallback until the content
+// is ready.
+//
+// Components should update themselves, with Context.Update or
+// Context.Dispatch, once they are no longer pending.
+type Suspender interface {
+	Composer
+
+	// Pending reports whether the component is still loading its content.
+	Pending() bool
+}
+
+// Suspense returns an element that displays the given fallback while one of
+// the Suspender components within the given content is pending.
+//
+// The content is mounted and kept hidden while pending, which allows its
+// components to load their data. Suspense elements can be nested, a pending
+// component being only handled by its closest enclosing Suspense element.
+//
+// When a page is rendered server-side, the Handler waits for the functions
+// started with Context.Async, such as in OnPreRender, to return before
+// rendering the page. A Suspense element whose components are no longer
+// pending at that point renders its content, otherwise it renders its fallback
+// and includes its content hidden.
+//
+// Example:
+//
+//	app.Suspense(
+//		app.P().Text("Loading..."),
+//		&userProfile{ID: 42},
+//		&userPosts{ID: 42},
+//	)
+func Suspense(fallback UI, content ...UI) UI {
+	return &suspense{
+		Fallback: fallback,
+		Content:  FilterUIElems(content...),
+	}
+}
+
+type suspense struct {
+	Compo
+
+	Fallback UI
+	Content  []UI
+}
+
+func (s *suspense) Render() UI {
+	pending := s.pending()
+	contentDisplay := "contents"
+	if pending {
+		contentDisplay = "none"
+	}
+
+	// The content is kept first in order to not be remounted when the
+	// fallback is removed.
+	return Div().
+		Style("display", "contents").
+		Body(
+			Div().
+				Style("display", contentDisplay).
+				Body(s.Content...),
+			If(pending, func() UI {
+				return s.Fallback
+			}),
+		)
+}
+
+// pending reports whether a Suspender in the mounted content is pending. The
+// content elements are checked when the content is not mounted yet.
+func (s *suspense) pending() bool {
+	if root, ok := s.root().(HTML); ok && root.Mounted() {
+		body := root.body()
+		if len(body) == 0 {
+			return false
+		}
+		return suspended(body[0])
+	}
+
+	for _, c := range s.Content {
+		if suspended(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// suspended reports whether the given element or one of its descendants is a
+// pending Suspender. Nested Suspense elements are not traversed.
+func suspended(v UI) bool {
+	switch v := v.(type) {
+	case *suspense:
+		return false
+
+	case Suspender:
+		if v.Pending() {
+			return true
+		}
+		if v.Mounted() {
+			return suspended(v.root())
+		}
+		return false
+
+	case Composer:
+		if v.Mounted() {
+			return suspended(v.root())
+		}
+		return false
+
+	case HTML:
+		for _, child := range v.body() {
+			if suspended(child) {
+				return true
+			}
+		}
+		return false
+
+	case *portal:
+		for _, child := range v.body() {
+			if suspended(child) {
+				return true
+			}
+		}
+		return false
+
+	default:
+		return false
+	}
+}
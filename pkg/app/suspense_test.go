@@ -0,0 +1,144 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/suspense-test/loaded", func() Composer {
+		return &suspenseTestCompo{content: &preRenderLoader{}}
+	})
+	Route("/suspense-test/pending", func() Composer {
+		return &suspenseTestCompo{content: &suspenderComponent{}}
+	})
+}
+
+type suspenseTestCompo struct {
+	Compo
+
+	content UI
+}
+
+func (c *suspenseTestCompo) Render() UI {
+	return Suspense(P().Text("loading"), c.content)
+}
+
+type preRenderLoader struct {
+	Compo
+
+	loaded bool
+}
+
+func (c *preRenderLoader) Pending() bool {
+	return !c.loaded
+}
+
+func (c *preRenderLoader) OnPreRender(ctx Context) {
+	ctx.Async(func() {
+		ctx.Dispatch(func(ctx Context) {
+			c.loaded = true
+		})
+	})
+}
+
+func (c *preRenderLoader) Render() UI {
+	return Text("prerendered")
+}
+
+type suspenderComponent struct {
+	Compo
+
+	loaded bool
+}
+
+func (c *suspenderComponent) Pending() bool {
+	return !c.loaded
+}
+
+func (c *suspenderComponent) Render() UI {
+	return Text("loaded")
+}
+
+func TestSuspense(t *testing.T) {
+	t.Run("fallback is displayed while pending", func(t *testing.T) {
+		e := newTestEngine()
+		loader := &suspenderComponent{}
+		require.NoError(t, e.Load(&foo{}))
+
+		s := Suspense(Text("loading"), Div().Body(loader)).(*suspense)
+		_, err := e.nodes.Mount(e.baseContext(), 1, s)
+		require.NoError(t, err)
+		require.True(t, s.pending())
+		require.True(t, loader.Mounted())
+
+		html := HTMLString(s)
+		require.Contains(t, html, "loading")
+		require.Contains(t, html, "display:none")
+
+		loader.loaded = true
+		require.False(t, s.pending())
+		_, err = e.nodes.UpdateComponentRoot(e.baseContext(), s)
+		require.NoError(t, err)
+
+		html = HTMLString(s)
+		require.NotContains(t, html, "loading")
+		require.NotContains(t, html, "display:none")
+	})
+
+	t.Run("content without suspender is not pending", func(t *testing.T) {
+		s := Suspense(Text("loading"), Text("hello"), &hello{}).(*suspense)
+		require.False(t, s.pending())
+	})
+
+	t.Run("nested suspense is not traversed", func(t *testing.T) {
+		s := Suspense(Text("loading"), Suspense(Text("nested loading"), &suspenderComponent{})).(*suspense)
+		require.False(t, s.pending())
+	})
+
+	t.Run("suspense is updated when a child dispatches", func(t *testing.T) {
+		e := newTestEngine()
+		loader := &suspenderComponent{}
+		root := &errorBoundaryComponent{Child: Suspense(Text("loading"), loader)}
+		require.NoError(t, e.Load(root))
+		e.ConsumeAll()
+		require.Contains(t, HTMLString(root), "loading")
+
+		e.nodes.context(e.baseContext(), loader).Dispatch(func(ctx Context) {
+			loader.loaded = true
+		})
+		e.ConsumeAll()
+		require.NotContains(t, HTMLString(root), "loading")
+	})
+}
+
+func TestHandlerServePageWithSuspense(t *testing.T) {
+	testSkipWasm(t)
+
+	t.Run("content loaded during pre-rendering is rendered", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/suspense-test/loaded", nil)
+		w := httptest.NewRecorder()
+
+		h := Handler{}
+		h.ServeHTTP(w, r)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), "prerendered")
+		require.NotContains(t, w.Body.String(), "loading")
+	})
+
+	t.Run("pending content is rendered hidden with the fallback", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/suspense-test/pending", nil)
+		w := httptest.NewRecorder()
+
+		h := Handler{}
+		h.ServeHTTP(w, r)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), "loading")
+		require.Contains(t, w.Body.String(), "display:none")
+	})
+}
@@ -114,6 +114,18 @@ func TestMatch(root UI, d TestUIDescriptor) error {
 			d.Path = d.Path[1:]
 			return TestMatch(children[index], d)
 
+		case *portal:
+			children := root.body()
+			if index < 0 || index >= len(children) {
+				return errors.New("element to match is out of range").
+					WithTag("type", reflect.TypeOf(d.Expected)).
+					WithTag("parent-type", reflect.TypeOf(root)).
+					WithTag("parent-children-count", len(children)).
+					WithTag("index", index)
+			}
+			d.Path = d.Path[1:]
+			return TestMatch(children[index], d)
+
 		case Composer:
 			if index != 0 {
 				return errors.New("element to match is out of range").
@@ -150,6 +162,9 @@ func match(n UI, d TestUIDescriptor) error {
 	case *raw:
 		return matchRaw(n.(*raw), d)
 
+	case *portal:
+		return matchPortal(n.(*portal), d)
+
 	default:
 		return errors.New("unsupported element").
 			WithTag("type", reflect.TypeOf(n))
@@ -281,3 +296,18 @@ func matchRaw(n *raw, d TestUIDescriptor) error {
 
 	return nil
 }
+
+func matchPortal(n *portal, d TestUIDescriptor) error {
+	a := n
+	b := d.Expected.(*portal)
+
+	if a.target != b.target {
+		return errors.New("the portal is not matching with the descriptor").
+			WithTag("type", reflect.TypeOf(n)).
+			WithTag("reason", "unexpected target").
+			WithTag("expected-target", b.target).
+			WithTag("current-target", a.target)
+	}
+
+	return nil
+}
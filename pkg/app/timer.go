@@ -0,0 +1,179 @@
+package app
+
+import (
+	"sync"
+	"time"
+)
+
+// Timer represents a function scheduled with Context.AfterFunc.
+type Timer struct {
+	stop func() bool
+}
+
+// Stop cancels the scheduled function. It reports whether the call stopped
+// the function before it was executed.
+func (t Timer) Stop() bool {
+	if t.stop == nil {
+		return false
+	}
+	return t.stop()
+}
+
+type debounceKey string
+
+type throttleKey string
+
+type timerEntry struct {
+	timer   *time.Timer
+	pending func(Context)
+}
+
+// timerManager manages the timers scheduled by UI elements. Timers whose
+// source is no longer mounted are stopped during cleanup.
+type timerManager struct {
+	mutex  sync.Mutex
+	timers map[UI]map[any]*timerEntry
+}
+
+// AfterFunc schedules the given function to be executed on the UI goroutine
+// after the given duration.
+func (m *timerManager) AfterFunc(ctx Context, d time.Duration, f func(Context)) Timer {
+	entry := &timerEntry{}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entry.timer = time.AfterFunc(d, func() {
+		if m.remove(ctx.sourceElement, entry, entry) {
+			ctx.Dispatch(f)
+		}
+	})
+	m.set(ctx.sourceElement, entry, entry)
+
+	return Timer{
+		stop: func() bool {
+			return m.remove(ctx.sourceElement, entry, entry) && entry.timer.Stop()
+		},
+	}
+}
+
+// Debounce schedules the given function to be executed on the UI goroutine
+// once the given duration elapsed without another call with the same key and
+// source.
+func (m *timerManager) Debounce(ctx Context, key string, d time.Duration, f func(Context)) {
+	k := debounceKey(key)
+	entry := &timerEntry{}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if previous := m.get(ctx.sourceElement, k); previous != nil {
+		previous.timer.Stop()
+	}
+
+	entry.timer = time.AfterFunc(d, func() {
+		if m.remove(ctx.sourceElement, k, entry) {
+			ctx.Dispatch(f)
+		}
+	})
+	m.set(ctx.sourceElement, k, entry)
+}
+
+// Throttle executes the given function on the UI goroutine at most once per
+// the given duration for a key and source. The function of the last call made
+// while throttled is executed at the end of the duration.
+func (m *timerManager) Throttle(ctx Context, key string, d time.Duration, f func(Context)) {
+	k := throttleKey(key)
+
+	m.mutex.Lock()
+	if entry := m.get(ctx.sourceElement, k); entry != nil {
+		entry.pending = f
+		m.mutex.Unlock()
+		return
+	}
+
+	entry := &timerEntry{}
+	var tick func()
+	tick = func() {
+		m.mutex.Lock()
+		if m.get(ctx.sourceElement, k) != entry {
+			m.mutex.Unlock()
+			return
+		}
+
+		pending := entry.pending
+		if pending == nil {
+			m.delete(ctx.sourceElement, k)
+			m.mutex.Unlock()
+			return
+		}
+		entry.pending = nil
+		entry.timer = time.AfterFunc(d, tick)
+		m.mutex.Unlock()
+
+		ctx.Dispatch(pending)
+	}
+
+	entry.timer = time.AfterFunc(d, tick)
+	m.set(ctx.sourceElement, k, entry)
+	m.mutex.Unlock()
+
+	ctx.Dispatch(f)
+}
+
+// Cleanup stops the timers of the sources that are no longer mounted. Timers
+// scheduled from a context without source element, such as the engine one, are
+// not bound to a lifecycle and are kept until they are executed or stopped.
+func (m *timerManager) Cleanup() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for source, timers := range m.timers {
+		if source == nil || source.Mounted() {
+			continue
+		}
+
+		for _, entry := range timers {
+			entry.timer.Stop()
+		}
+		delete(m.timers, source)
+	}
+}
+
+func (m *timerManager) get(source UI, key any) *timerEntry {
+	return m.timers[source][key]
+}
+
+func (m *timerManager) set(source UI, key any, entry *timerEntry) {
+	if m.timers == nil {
+		m.timers = make(map[UI]map[any]*timerEntry)
+	}
+
+	timers := m.timers[source]
+	if timers == nil {
+		timers = make(map[any]*timerEntry)
+		m.timers[source] = timers
+	}
+	timers[key] = entry
+}
+
+func (m *timerManager) delete(source UI, key any) {
+	timers := m.timers[source]
+	delete(timers, key)
+	if len(timers) == 0 {
+		delete(m.timers, source)
+	}
+}
+
+// remove deletes the timer entry registered with the given key when it is the
+// given one. It reports whether the entry was removed.
+func (m *timerManager) remove(source UI, key any, entry *timerEntry) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.get(source, key) != entry {
+		return false
+	}
+	m.delete(source, key)
+	return true
+}
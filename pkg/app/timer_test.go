@@ -0,0 +1,146 @@
+package app
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimer(t *testing.T) {
+	var timer Timer
+	require.False(t, timer.Stop())
+}
+
+func TestTimerManager(t *testing.T) {
+	newContext := func(t *testing.T) Context {
+		var nm nodeManager
+		ctx := makeTestContext()
+		source, err := nm.Mount(ctx, 1, Div())
+		require.NoError(t, err)
+		return nm.context(ctx, source)
+	}
+
+	t.Run("after func is executed", func(t *testing.T) {
+		var m timerManager
+		ctx := newContext(t)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		m.AfterFunc(ctx, time.Millisecond, func(Context) {
+			wg.Done()
+		})
+		wg.Wait()
+		require.Empty(t, m.timers)
+	})
+
+	t.Run("after func is stopped", func(t *testing.T) {
+		var m timerManager
+		ctx := newContext(t)
+
+		timer := m.AfterFunc(ctx, time.Hour, func(Context) {})
+		require.Len(t, m.timers, 1)
+		require.True(t, timer.Stop())
+		require.False(t, timer.Stop())
+		require.Empty(t, m.timers)
+	})
+
+	t.Run("debounce executes the last call", func(t *testing.T) {
+		var m timerManager
+		ctx := newContext(t)
+
+		calls := make(chan int, 3)
+		for i := 0; i < 3; i++ {
+			i := i
+			m.Debounce(ctx, "test", time.Millisecond*10, func(Context) {
+				calls <- i
+			})
+		}
+
+		require.Equal(t, 2, <-calls)
+		time.Sleep(time.Millisecond * 20)
+		require.Empty(t, calls)
+	})
+
+	t.Run("throttle executes the first and last calls", func(t *testing.T) {
+		var m timerManager
+		ctx := newContext(t)
+
+		calls := make(chan int, 3)
+		for i := 0; i < 3; i++ {
+			i := i
+			m.Throttle(ctx, "test", time.Millisecond*10, func(Context) {
+				calls <- i
+			})
+		}
+
+		require.Equal(t, 0, <-calls)
+		require.Equal(t, 2, <-calls)
+		time.Sleep(time.Millisecond * 30)
+		require.Empty(t, calls)
+
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		require.Empty(t, m.timers)
+	})
+
+	t.Run("timers of dismounted sources are stopped", func(t *testing.T) {
+		var m timerManager
+		var nm nodeManager
+		ctx := makeTestContext()
+		source, err := nm.Mount(ctx, 1, Div())
+		require.NoError(t, err)
+		ctx = nm.context(ctx, source)
+
+		called := false
+		m.Debounce(ctx, "test", time.Millisecond*10, func(Context) {
+			called = true
+		})
+		m.AfterFunc(ctx, time.Hour, func(Context) {})
+		require.Len(t, m.timers[source], 2)
+
+		m.Cleanup()
+		require.Len(t, m.timers, 1)
+
+		nm.Dismount(source)
+		m.Cleanup()
+		require.Empty(t, m.timers)
+
+		time.Sleep(time.Millisecond * 20)
+		require.False(t, called)
+	})
+
+	t.Run("timers without source are kept", func(t *testing.T) {
+		var m timerManager
+		ctx := makeTestContext()
+
+		timer := m.AfterFunc(ctx, time.Hour, func(Context) {})
+		require.NotPanics(t, m.Cleanup)
+		require.Len(t, m.timers, 1)
+		require.True(t, timer.Stop())
+	})
+}
+
+func TestContextDebounce(t *testing.T) {
+	e := newTestEngine()
+	hello := &hello{}
+	e.Load(hello)
+	ctx := e.nodes.context(e.baseContext(), hello)
+
+	done := make(chan struct{})
+	ctx.Debounce("test", time.Millisecond, func(Context) {
+		close(done)
+	})
+	ctx.Throttle("test", time.Millisecond, func(Context) {})
+	ctx.AfterFunc(time.Hour, func(Context) {}).Stop()
+
+	for {
+		e.ConsumeAll()
+		select {
+		case <-done:
+			return
+		case <-time.After(time.Millisecond * 5):
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	defaultTransitionDuration = 200 * time.Millisecond
+	defaultTransitionEasing   = "ease"
+)
+
+// Keyframe represents a set of CSS properties to animate, as defined by the
+// Web Animations API. Property names are in camel case, such as
+// "backgroundColor".
+type Keyframe map[string]any
+
+// TransitionGroup is the interface that describes an element that animates its
+// children when they are added, removed or moved.
+type TransitionGroup interface {
+	UI
+
+	// Name sets the prefix of the CSS classes applied to the children while
+	// they are added or removed:
+	//  - <name>-enter-from, <name>-enter-active and <name>-enter-to while a
+	//    child is added
+	//  - <name>-leave-from, <name>-leave-active and <name>-leave-to while a
+	//    child is removed
+	//
+	// The -from class is applied when the phase starts and replaced by the -to
+	// class right after, which allows CSS transitions and animations defined
+	// with the -active class to run.
+	Name(v string) TransitionGroup
+
+	// Enter sets the Web Animations API keyframes played when a child is
+	// added.
+	Enter(keyframes ...Keyframe) TransitionGroup
+
+	// Leave sets the Web Animations API keyframes played when a child is
+	// removed.
+	Leave(keyframes ...Keyframe) TransitionGroup
+
+	// Move sets whether the children are animated from their previous position
+	// when they are moved by an update. Children are identified by their ID
+	// across updates. Default is false.
+	Move(v bool) TransitionGroup
+
+	// Duration sets the duration of the keyframe and move animations. Default
+	// is 200ms.
+	Duration(v time.Duration) TransitionGroup
+
+	// Easing sets the CSS timing function of the keyframe and move animations.
+	// Default is "ease".
+	Easing(v string) TransitionGroup
+
+	// Body sets the animated children.
+	Body(v ...UI) TransitionGroup
+}
+
+// Transition returns an element that animates the given children when they are
+// added, removed or moved by an update.
+//
+// Removed children are dismounted right away, but their HTML element is kept
+// in the page until their leave animations and transitions are finished.
+// Children mounted with the transition element itself are not animated.
+//
+// Example:
+//
+//	app.Transition(
+//		app.If(c.open, func() app.UI {
+//			return app.Div().Text("hello")
+//		}),
+//	).
+//		Enter(app.Keyframe{"opacity": 0}, app.Keyframe{"opacity": 1}).
+//		Leave(app.Keyframe{"opacity": 1}, app.Keyframe{"opacity": 0})
+func Transition(children ...UI) TransitionGroup {
+	return &transition{
+		Iduration: defaultTransitionDuration,
+		Ieasing:   defaultTransitionEasing,
+		Ibody:     FilterUIElems(children...),
+	}
+}
+
+type transition struct {
+	Compo
+
+	Iname     string
+	Ienter    []Keyframe
+	Ileave    []Keyframe
+	Imove     bool
+	Iduration time.Duration
+	Ieasing   string
+	Ibody     []UI
+}
+
+func (t *transition) Name(v string) TransitionGroup {
+	t.Iname = v
+	return t
+}
+
+func (t *transition) Enter(keyframes ...Keyframe) TransitionGroup {
+	t.Ienter = keyframes
+	return t
+}
+
+func (t *transition) Leave(keyframes ...Keyframe) TransitionGroup {
+	t.Ileave = keyframes
+	return t
+}
+
+func (t *transition) Move(v bool) TransitionGroup {
+	t.Imove = v
+	return t
+}
+
+func (t *transition) Duration(v time.Duration) TransitionGroup {
+	if v > 0 {
+		t.Iduration = v
+	}
+	return t
+}
+
+func (t *transition) Easing(v string) TransitionGroup {
+	if v != "" {
+		t.Ieasing = v
+	}
+	return t
+}
+
+func (t *transition) Body(v ...UI) TransitionGroup {
+	t.Ibody = FilterUIElems(v...)
+	return t
+}
+
+func (t *transition) Render() UI {
+	return Div().
+		Style("display", "contents").
+		Body(t.Ibody...)
+}
+
+// transitionOf returns the transition element whose children are the children
+// of the given element. Transitions are only played on the client.
+func transitionOf(v UI) (*transition, bool) {
+	if !IsClient || v.parent() == nil {
+		return nil, false
+	}
+
+	t, ok := v.parent().(*transition)
+	if !ok || t.root() != v {
+		return nil, false
+	}
+	return t, true
+}
+
+// enter plays the enter phase on the given child.
+func (t *transition) enter(child UI) {
+	elem := child.JSValue()
+	if !isElementNode(elem) {
+		return
+	}
+
+	t.play(elem, "enter", t.Ienter, func() {})
+}
+
+// leave plays the leave phase on the given child and removes its HTML element
+// from the page once finished. It must be called before the child is
+// dismounted.
+func (t *transition) leave(child UI) {
+	elem := child.JSValue()
+	if !isElementNode(elem) {
+		elem.Call("remove")
+		return
+	}
+
+	t.play(elem, "leave", t.Ileave, func() {
+		elem.Call("remove")
+	})
+}
+
+func (t *transition) play(elem Value, phase string, keyframes []Keyframe, done func()) {
+	var from, active, to string
+	classes := elem.Get("classList")
+
+	if t.Iname != "" {
+		from = fmt.Sprintf("%s-%s-from", t.Iname, phase)
+		active = fmt.Sprintf("%s-%s-active", t.Iname, phase)
+		to = fmt.Sprintf("%s-%s-to", t.Iname, phase)
+
+		classes.Call("add", from, active)
+
+		// Reading the layout makes the browser apply the -from class before it
+		// is replaced.
+		elem.Get("offsetWidth")
+
+		classes.Call("remove", from)
+		classes.Call("add", to)
+	}
+
+	if len(keyframes) != 0 {
+		t.animate(elem, keyframesToJS(keyframes))
+	}
+
+	whenAnimationsFinished(elem, func() {
+		if t.Iname != "" {
+			classes.Call("remove", active, to)
+		}
+		done()
+	})
+}
+
+func (t *transition) animate(elem Value, keyframes []any) {
+	elem.Call("animate", keyframes, map[string]any{
+		"duration": t.Iduration.Milliseconds(),
+		"easing":   t.Ieasing,
+	})
+}
+
+type transitionPosition struct {
+	left float64
+	top  float64
+}
+
+// positions returns the position of the given children that have an ID. It
+// returns nil when the children moves are not animated.
+func (t *transition) positions(children []UI) map[string]transitionPosition {
+	if !t.Imove {
+		return nil
+	}
+
+	positions := make(map[string]transitionPosition, len(children))
+	for _, child := range children {
+		elem := child.JSValue()
+		if !isElementNode(elem) {
+			continue
+		}
+
+		if id := elem.Get("id").String(); id != "" {
+			positions[id] = elementPosition(elem)
+		}
+	}
+	return positions
+}
+
+// move animates the given children that have been moved from the given
+// positions, by inverting their move and playing it back to their new
+// position.
+func (t *transition) move(children []UI, positions map[string]transitionPosition) {
+	if len(positions) == 0 {
+		return
+	}
+
+	for _, child := range children {
+		elem := child.JSValue()
+		if !isElementNode(elem) {
+			continue
+		}
+
+		from, ok := positions[elem.Get("id").String()]
+		if !ok {
+			continue
+		}
+
+		to := elementPosition(elem)
+		dx := from.left - to.left
+		dy := from.top - to.top
+		if dx == 0 && dy == 0 {
+			continue
+		}
+
+		t.animate(elem, []any{
+			map[string]any{"transform": fmt.Sprintf("translate(%gpx, %gpx)", dx, dy)},
+			map[string]any{"transform": "none"},
+		})
+	}
+}
+
+func elementPosition(elem Value) transitionPosition {
+	rect := elem.Call("getBoundingClientRect")
+	return transitionPosition{
+		left: rect.Get("left").Float(),
+		top:  rect.Get("top").Float(),
+	}
+}
+
+// whenAnimationsFinished calls the given function once the finite animations
+// and transitions of the given element and its descendants are finished or
+// cancelled.
+func whenAnimationsFinished(elem Value, done func()) {
+	if !elem.Get("getAnimations").Truthy() {
+		done()
+		return
+	}
+
+	animations := elem.Call("getAnimations", map[string]any{"subtree": true})
+	finished := make([]any, 0, animations.Length())
+	for i := 0; i < animations.Length(); i++ {
+		animation := animations.Index(i)
+		effect := animation.Get("effect")
+		if !effect.Truthy() {
+			continue
+		}
+
+		endTime := effect.Call("getComputedTiming").Get("endTime").Float()
+		if math.IsInf(endTime, 1) {
+			continue
+		}
+		finished = append(finished, animation.Get("finished"))
+	}
+
+	if len(finished) == 0 {
+		done()
+		return
+	}
+
+	Window().Get("Promise").
+		Call("allSettled", finished).
+		Then(func(Value) {
+			done()
+		})
+}
+
+func isElementNode(v Value) bool {
+	return v != nil && v.Truthy() && v.Get("nodeType").Int() == 1
+}
+
+func keyframesToJS(keyframes []Keyframe) []any {
+	s := make([]any, len(keyframes))
+	for i, k := range keyframes {
+		s[i] = map[string]any(k)
+	}
+	return s
+}
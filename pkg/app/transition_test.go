@@ -0,0 +1,162 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransition(t *testing.T) {
+	t.Run("default transition", func(t *testing.T) {
+		tr := Transition(Div(), nil, Span()).(*transition)
+		require.Len(t, tr.Ibody, 2)
+		require.Equal(t, defaultTransitionDuration, tr.Iduration)
+		require.Equal(t, defaultTransitionEasing, tr.Ieasing)
+		require.False(t, tr.Imove)
+	})
+
+	t.Run("configured transition", func(t *testing.T) {
+		tr := Transition().
+			Name("fade").
+			Enter(Keyframe{"opacity": 0}, Keyframe{"opacity": 1}).
+			Leave(Keyframe{"opacity": 1}, Keyframe{"opacity": 0}).
+			Move(true).
+			Duration(time.Second).
+			Easing("linear").
+			Body(Div()).(*transition)
+		require.Equal(t, "fade", tr.Iname)
+		require.Len(t, tr.Ienter, 2)
+		require.Len(t, tr.Ileave, 2)
+		require.True(t, tr.Imove)
+		require.Equal(t, time.Second, tr.Iduration)
+		require.Equal(t, "linear", tr.Ieasing)
+		require.Len(t, tr.Ibody, 1)
+	})
+
+	t.Run("invalid duration and easing are ignored", func(t *testing.T) {
+		tr := Transition().
+			Duration(-time.Second).
+			Easing("").(*transition)
+		require.Equal(t, defaultTransitionDuration, tr.Iduration)
+		require.Equal(t, defaultTransitionEasing, tr.Ieasing)
+	})
+
+	t.Run("transition is rendered", func(t *testing.T) {
+		html := HTMLString(Transition(Span().Text("hello")))
+		require.Equal(t, "<div style=\"display:contents\">\n  <span>hello</span>\n</div>", html)
+	})
+}
+
+func TestTransitionUpdate(t *testing.T) {
+	testSkipWasm(t)
+
+	var m nodeManager
+	ctx := makeTestContext()
+
+	tr, err := m.Mount(ctx, 1, Transition(Span(), Div(), P()).
+		Leave(Keyframe{"opacity": 1}, Keyframe{"opacity": 0}))
+	require.NoError(t, err)
+
+	root := tr.(*transition).root()
+	_, animated := transitionOf(root)
+	require.False(t, animated)
+
+	tr, err = m.Update(ctx, tr, Transition(Span(), A()).
+		Leave(Keyframe{"opacity": 1}, Keyframe{"opacity": 0}))
+	require.NoError(t, err)
+
+	children := tr.(*transition).root().(HTML).body()
+	require.Len(t, children, 2)
+	require.IsType(t, &htmlSpan{}, children[0])
+	require.IsType(t, &htmlA{}, children[1])
+}
+
+func TestTransitionOf(t *testing.T) {
+	testSkipNonWasm(t)
+
+	var m nodeManager
+	ctx := makeTestContext()
+
+	tr, err := m.Mount(ctx, 1, Transition(Span()))
+	require.NoError(t, err)
+
+	res, animated := transitionOf(tr.(*transition).root())
+	require.True(t, animated)
+	require.Equal(t, tr, res)
+
+	_, animated = transitionOf(tr.(*transition).root().(HTML).body()[0])
+	require.False(t, animated)
+}
+
+func TestTransitionLeave(t *testing.T) {
+	testSkipNonWasm(t)
+
+	var m nodeManager
+	ctx := makeTestContext()
+	leave := []Keyframe{{"opacity": 1}, {"opacity": 0}}
+
+	tr, err := m.Mount(ctx, 1, Transition(Span(), Div()).
+		Leave(leave...).
+		Duration(time.Hour))
+	require.NoError(t, err)
+
+	body := Window().Get("document").Get("body")
+	body.Call("appendChild", tr.JSValue())
+	defer tr.JSValue().Call("remove")
+
+	removed := tr.(*transition).root().(HTML).body()[1]
+	elem := removed.JSValue()
+
+	tr, err = m.Update(ctx, tr, Transition(Span()).
+		Leave(leave...).
+		Duration(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, tr.(*transition).root().(HTML).body(), 1)
+	require.False(t, removed.Mounted())
+	require.True(t, elem.Get("isConnected").Bool())
+
+	animations := elem.Call("getAnimations")
+	require.Equal(t, 1, animations.Length())
+	animations.Index(0).Call("finish")
+
+	require.Eventually(t, func() bool {
+		return !elem.Get("isConnected").Bool()
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestWhenAnimationsFinished(t *testing.T) {
+	testSkipNonWasm(t)
+
+	t.Run("element without animations", func(t *testing.T) {
+		elem := Window().Get("document").Call("createElement", "div")
+
+		done := false
+		whenAnimationsFinished(elem, func() {
+			done = true
+		})
+		require.True(t, done)
+	})
+
+	t.Run("element with animations", func(t *testing.T) {
+		elem := Window().Get("document").Call("createElement", "div")
+		Window().Get("document").Get("body").Call("appendChild", elem)
+		defer elem.Call("remove")
+
+		elem.Call("animate", []any{
+			map[string]any{"opacity": 1},
+			map[string]any{"opacity": 0},
+		}, map[string]any{"duration": 10})
+
+		done := make(chan struct{})
+		whenAnimationsFinished(elem, func() {
+			close(done)
+		})
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			require.Fail(t, "animations are not finished")
+		}
+	})
+}
@@ -87,10 +87,11 @@ func (b *base) Render() app.UI {
 
 func (b *base) resize(ctx app.Context) {
 	w, _ := ctx.Page().Size()
-	if w <= 480 {
-		b.hpadding = BaseMobileHPadding
+	theme := CurrentTheme(ctx)
+	if ctx.MatchMedia(mobileQuery) {
+		b.hpadding = themeOrDefault(theme.BaseMobileHPadding, BaseMobileHPadding)
 	} else {
-		b.hpadding = BaseHPadding
+		b.hpadding = themeOrDefault(theme.BaseHPadding, BaseHPadding)
 	}
 
 	if w != b.width {
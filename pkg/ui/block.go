@@ -137,12 +137,13 @@ func (b *block) Render() app.UI {
 func (b *block) resize(ctx app.Context) {
 	w, _ := ctx.Page().Size()
 
+	theme := CurrentTheme(ctx)
 	var padding int
 	if b.Ipadding {
-		if w <= 480 {
-			padding = BlockMobilePadding
+		if ctx.MatchMedia(mobileQuery) {
+			padding = themeOrDefault(theme.BlockMobilePadding, BlockMobilePadding)
 		} else {
-			padding = BlockPadding
+			padding = themeOrDefault(theme.BlockPadding, BlockPadding)
 		}
 	}
 
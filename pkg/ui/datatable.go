@@ -0,0 +1,787 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v11/pkg/app"
+)
+
+const (
+	defaultColumnWidth      = 160
+	minColumnWidth          = 48
+	selectionColumnWidth    = 48
+	columnResizeHandleWidth = 6
+)
+
+// IColumn is the interface that describes a column of a data table that
+// displays rows of type T.
+type IColumn[T any] interface {
+	// Sets the ID that identifies the column across updates, such as to keep
+	// its resized width when columns are added, removed or reordered. Default
+	// is the title.
+	ID(v string) IColumn[T]
+
+	// Sets the width in px. Default is 160px.
+	Width(px int) IColumn[T]
+
+	// Sets the function that returns the text of a cell. The text is
+	// displayed when no cell rendering function is set, and is used to sort
+	// and filter the rows.
+	Text(f func(row T) string) IColumn[T]
+
+	// Sets the function that renders a cell.
+	Cell(f func(row T) app.UI) IColumn[T]
+
+	// Sets the function that reports whether a row is sorted before another
+	// one. Rows are sorted by text when it is not set.
+	Less(f func(a, b T) bool) IColumn[T]
+
+	// Sets whether the rows can be sorted by the column. Default is true when
+	// a text or a less function is set.
+	Sortable(v bool) IColumn[T]
+
+	column() *column[T]
+}
+
+// Column creates a column of a data table with the given title.
+func Column[T any](title string) IColumn[T] {
+	return &column[T]{
+		id:    title,
+		title: title,
+		width: defaultColumnWidth,
+	}
+}
+
+type column[T any] struct {
+	id       string
+	title    string
+	width    int
+	text     func(T) string
+	cell     func(T) app.UI
+	less     func(a, b T) bool
+	sortable *bool
+}
+
+func (c *column[T]) ID(v string) IColumn[T] {
+	c.id = v
+	return c
+}
+
+func (c *column[T]) Width(px int) IColumn[T] {
+	if px > 0 {
+		c.width = max(px, minColumnWidth)
+	}
+	return c
+}
+
+func (c *column[T]) Text(f func(T) string) IColumn[T] {
+	c.text = f
+	return c
+}
+
+func (c *column[T]) Cell(f func(T) app.UI) IColumn[T] {
+	c.cell = f
+	return c
+}
+
+func (c *column[T]) Less(f func(a, b T) bool) IColumn[T] {
+	c.less = f
+	return c
+}
+
+func (c *column[T]) Sortable(v bool) IColumn[T] {
+	c.sortable = &v
+	return c
+}
+
+func (c *column[T]) column() *column[T] {
+	return c
+}
+
+func (c *column[T]) isSortable() bool {
+	if c.sortable != nil {
+		return *c.sortable && (c.text != nil || c.less != nil)
+	}
+	return c.text != nil || c.less != nil
+}
+
+func (c *column[T]) renderCell(row T) app.UI {
+	switch {
+	case c.cell != nil:
+		return c.cell(row)
+
+	case c.text != nil:
+		return app.Text(c.text(row))
+
+	default:
+		return nil
+	}
+}
+
+func (c *column[T]) compare(a, b T) bool {
+	if c.less != nil {
+		return c.less(a, b)
+	}
+	return c.text(a) < c.text(b)
+}
+
+// IDataTable is the interface that describes a table that displays rows of
+// type T.
+//
+// Rows can be sorted by clicking on column headers, filtered with a search
+// field, split into pages, and selected. Columns can be resized by dragging
+// the right edge of their header, and headers stick to the top of the table
+// while scrolling. The rows of the first page are rendered on the server.
+type IDataTable[T any] interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IDataTable[T]
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IDataTable[T]
+
+	// Sets the accessible caption.
+	Caption(v string) IDataTable[T]
+
+	// Sets the columns.
+	Columns(v ...IColumn[T]) IDataTable[T]
+
+	// Sets the rows.
+	Rows(v ...T) IDataTable[T]
+
+	// Sets the number of rows displayed per page. A size less than or equal to
+	// 0 displays all the rows. Default is 0.
+	PageSize(v int) IDataTable[T]
+
+	// Sets whether a search field that filters the rows by the text of their
+	// cells is displayed. Default is false.
+	Searchable(v bool) IDataTable[T]
+
+	// Sets whether rows can be selected. Default is false.
+	Selectable(v bool) IDataTable[T]
+
+	// Sets the function that returns the key identifying a row. Selected rows
+	// are tracked by their key when the rows are changed. The selection is
+	// cleared when the rows are changed and no key function is set.
+	RowKey(f func(row T) string) IDataTable[T]
+
+	// Sets the function called with the selected rows when the selection
+	// changes.
+	OnSelect(h func(ctx app.Context, rows []T)) IDataTable[T]
+
+	// Sets the content displayed when there are no rows to display.
+	Empty(v ...app.UI) IDataTable[T]
+
+	// Sets the texts of the search field, the selection checkboxes and the
+	// pagination.
+	Labels(v DataTableLabels) IDataTable[T]
+}
+
+// DataTableLabels contains the texts that a data table displays or announces
+// to assistive technologies.
+type DataTableLabels struct {
+	// The placeholder and accessible label of the search field. Default is
+	// "Search".
+	Search string
+
+	// The accessible label of the checkbox that selects all the rows. Default
+	// is "Select all rows".
+	SelectAll string
+
+	// The accessible label of the checkbox that selects a row. Default is
+	// "Select row".
+	SelectRow string
+
+	// The accessible label of the pagination. Default is "Pagination".
+	Pagination string
+
+	// The accessible label of the button that displays the previous page.
+	// Default is "Previous page".
+	PreviousPage string
+
+	// The accessible label of the button that displays the next page. Default
+	// is "Next page".
+	NextPage string
+
+	// Returns the text that describes the displayed rows, from the position of
+	// the first one, starting at 1, to the position of the last one, and the
+	// number of rows. Default is "<first>–<last> of <count>".
+	Range func(first, last, count int) string
+}
+
+func (l DataTableLabels) withDefaults() DataTableLabels {
+	if l.Search == "" {
+		l.Search = "Search"
+	}
+	if l.SelectAll == "" {
+		l.SelectAll = "Select all rows"
+	}
+	if l.SelectRow == "" {
+		l.SelectRow = "Select row"
+	}
+	if l.Pagination == "" {
+		l.Pagination = "Pagination"
+	}
+	if l.PreviousPage == "" {
+		l.PreviousPage = "Previous page"
+	}
+	if l.NextPage == "" {
+		l.NextPage = "Next page"
+	}
+	if l.Range == nil {
+		l.Range = func(first, last, count int) string {
+			return fmt.Sprintf("%v–%v of %v", first, last, count)
+		}
+	}
+	return l
+}
+
+// DataTable creates a table that displays rows of type T.
+func DataTable[T any]() IDataTable[T] {
+	return &dataTable[T]{
+		sortColumn: -1,
+		resizing:   -1,
+	}
+}
+
+type dataTable[T any] struct {
+	app.Compo
+
+	Iid         string
+	Iclass      string
+	Icaption    string
+	Icolumns    []IColumn[T]
+	Irows       []T
+	IpageSize   int
+	Isearchable bool
+	Iselectable bool
+	IrowKey     func(T) string
+	IonSelect   func(app.Context, []T)
+	Iempty      []app.UI
+	Ilabels     DataTableLabels
+
+	rows       []T
+	query      string
+	sortColumn int
+	sortDesc   bool
+	page       int
+	widths     map[string]int
+	selected   map[string]bool
+	view       []int
+	pages      int
+	resizing   int
+	resizeX    int
+	resizeFrom int
+}
+
+func (t *dataTable[T]) ID(v string) IDataTable[T] {
+	t.Iid = v
+	return t
+}
+
+func (t *dataTable[T]) Class(v string) IDataTable[T] {
+	t.Iclass = app.AppendClass(t.Iclass, v)
+	return t
+}
+
+func (t *dataTable[T]) Caption(v string) IDataTable[T] {
+	t.Icaption = v
+	return t
+}
+
+func (t *dataTable[T]) Columns(v ...IColumn[T]) IDataTable[T] {
+	t.Icolumns = v
+	return t
+}
+
+func (t *dataTable[T]) Rows(v ...T) IDataTable[T] {
+	t.Irows = v
+	return t
+}
+
+func (t *dataTable[T]) PageSize(v int) IDataTable[T] {
+	t.IpageSize = max(v, 0)
+	return t
+}
+
+func (t *dataTable[T]) Searchable(v bool) IDataTable[T] {
+	t.Isearchable = v
+	return t
+}
+
+func (t *dataTable[T]) Selectable(v bool) IDataTable[T] {
+	t.Iselectable = v
+	return t
+}
+
+func (t *dataTable[T]) RowKey(f func(T) string) IDataTable[T] {
+	t.IrowKey = f
+	return t
+}
+
+func (t *dataTable[T]) OnSelect(h func(app.Context, []T)) IDataTable[T] {
+	t.IonSelect = h
+	return t
+}
+
+func (t *dataTable[T]) Empty(v ...app.UI) IDataTable[T] {
+	t.Iempty = app.FilterUIElems(v...)
+	return t
+}
+
+func (t *dataTable[T]) Labels(v DataTableLabels) IDataTable[T] {
+	t.Ilabels = v
+	return t
+}
+
+func (t *dataTable[T]) OnPreRender(ctx app.Context) {
+	t.rows = t.Irows
+	t.refresh()
+}
+
+func (t *dataTable[T]) OnMount(ctx app.Context) {
+	t.rows = t.Irows
+	t.refresh()
+}
+
+func (t *dataTable[T]) OnUpdate(ctx app.Context) {
+	if t.sortColumn >= len(t.Icolumns) ||
+		(t.sortColumn >= 0 && !t.Icolumns[t.sortColumn].column().isSortable()) {
+		t.sortColumn = -1
+		t.sortDesc = false
+	}
+
+	if !sameRows(t.rows, t.Irows) {
+		t.rows = t.Irows
+		t.pruneSelection()
+	}
+	t.refresh()
+}
+
+// pruneSelection removes the keys of the rows that are no longer displayed
+// from the selection, or clears it when rows are not identified by a key.
+func (t *dataTable[T]) pruneSelection() {
+	if t.IrowKey == nil {
+		t.selected = nil
+		return
+	}
+
+	selected := make(map[string]bool, len(t.selected))
+	for _, row := range t.Irows {
+		if key := t.IrowKey(row); t.selected[key] {
+			selected[key] = true
+		}
+	}
+	t.selected = selected
+}
+
+// refresh computes the indexes of the rows to display, filtered by the search
+// query, sorted by the sort column, and restricted to the current page.
+func (t *dataTable[T]) refresh() {
+	query := strings.ToLower(strings.TrimSpace(t.query))
+	view := make([]int, 0, len(t.Irows))
+	for i, row := range t.Irows {
+		if query == "" || t.matches(row, query) {
+			view = append(view, i)
+		}
+	}
+
+	if t.sortColumn >= 0 && t.sortColumn < len(t.Icolumns) && t.Icolumns[t.sortColumn].column().isSortable() {
+		c := t.Icolumns[t.sortColumn].column()
+		sort.SliceStable(view, func(i, j int) bool {
+			a, b := t.Irows[view[i]], t.Irows[view[j]]
+			if t.sortDesc {
+				return c.compare(b, a)
+			}
+			return c.compare(a, b)
+		})
+	}
+
+	t.pages = 1
+	if t.IpageSize > 0 && len(view) > t.IpageSize {
+		t.pages = (len(view) + t.IpageSize - 1) / t.IpageSize
+	}
+	t.page = min(max(t.page, 0), t.pages-1)
+
+	if t.IpageSize > 0 {
+		start := t.page * t.IpageSize
+		end := min(start+t.IpageSize, len(view))
+		view = view[start:end]
+	}
+	t.view = view
+}
+
+func (t *dataTable[T]) matches(row T, query string) bool {
+	for _, c := range t.Icolumns {
+		if c := c.column(); c.text != nil && strings.Contains(strings.ToLower(c.text(row)), query) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *dataTable[T]) Render() app.UI {
+	if t.view == nil && len(t.Irows) != 0 {
+		t.refresh()
+	}
+	labels := t.Ilabels.withDefaults()
+
+	return app.Div().
+		DataSet("goapp-ui", "data-table").
+		ID(t.Iid).
+		Class(t.Iclass).
+		Body(
+			app.If(t.Isearchable, func() app.UI {
+				return app.Input().
+					Type("search").
+					Placeholder(labels.Search).
+					Aria("label", labels.Search).
+					Value(t.query).
+					Style("margin-bottom", pxToString(BaseVPadding)).
+					OnInput(t.onSearch)
+			}),
+			app.Div().
+				Style("overflow", "auto").
+				Style("max-height", "100%").
+				Body(
+					app.Table().
+						Style("table-layout", "fixed").
+						Style("border-collapse", "collapse").
+						Style("width", pxToString(t.tableWidth())).
+						Body(
+							app.If(t.Icaption != "", func() app.UI {
+								return app.Caption().Text(t.Icaption)
+							}),
+							t.renderColGroup(),
+							app.THead().Body(
+								app.Tr().Body(
+									app.If(t.Iselectable, func() app.UI {
+										return t.renderHeader(app.Input().
+											Type("checkbox").
+											Aria("label", labels.SelectAll).
+											Checked(t.allSelected()).
+											OnChange(t.onSelectAll))
+									}),
+									app.Range(t.Icolumns).Slice(func(i int) app.UI {
+										return t.renderColumnHeader(i)
+									}),
+								),
+							),
+							app.TBody().Body(
+								app.If(len(t.view) == 0, func() app.UI {
+									return app.Tr().Body(
+										app.Td().
+											ColSpan(t.columnCount()).
+											Body(t.Iempty...),
+									)
+								}),
+								app.Range(t.view).Slice(func(i int) app.UI {
+									return t.renderRow(t.view[i], labels)
+								}),
+							),
+						),
+				),
+			app.If(t.pages > 1, func() app.UI {
+				return t.renderPagination(labels)
+			}),
+		)
+}
+
+func (t *dataTable[T]) renderColGroup() app.UI {
+	return app.ColGroup().Body(
+		app.If(t.Iselectable, func() app.UI {
+			return app.Col().Style("width", pxToString(selectionColumnWidth))
+		}),
+		app.Range(t.Icolumns).Slice(func(i int) app.UI {
+			return app.Col().Style("width", pxToString(t.columnWidth(i)))
+		}),
+	)
+}
+
+func (t *dataTable[T]) renderHeader(v ...app.UI) app.HTMLTh {
+	return app.Th().
+		Scope("col").
+		Style("position", "sticky").
+		Style("top", "0").
+		Style("z-index", "1").
+		Style("background-color", ColorSurface).
+		Style("text-align", "left").
+		Style("overflow", "hidden").
+		Style("text-overflow", "ellipsis").
+		Style("white-space", "nowrap").
+		Body(v...)
+}
+
+func (t *dataTable[T]) renderColumnHeader(i int) app.UI {
+	c := t.Icolumns[i].column()
+
+	sortOrder := "none"
+	indicator := ""
+	if i == t.sortColumn {
+		sortOrder = "ascending"
+		indicator = " ▲"
+		if t.sortDesc {
+			sortOrder = "descending"
+			indicator = " ▼"
+		}
+	}
+
+	var title app.UI = app.Text(c.title)
+	if c.isSortable() {
+		title = app.Button().
+			Type("button").
+			Style("all", "unset").
+			Style("cursor", "pointer").
+			OnClick(func(ctx app.Context, e app.Event) {
+				t.sort(i)
+			}).
+			Text(c.title + indicator)
+	}
+
+	return t.renderHeader(
+		title,
+		app.Div().
+			Aria("hidden", true).
+			Style("position", "absolute").
+			Style("top", "0").
+			Style("right", "0").
+			Style("width", pxToString(columnResizeHandleWidth)).
+			Style("height", "100%").
+			Style("cursor", "col-resize").
+			Style("touch-action", "none").
+			On("pointerdown", func(ctx app.Context, e app.Event) {
+				t.startResize(e, i)
+			}).
+			On("pointermove", t.resize).
+			On("pointerup", t.endResize).
+			On("pointercancel", t.endResize),
+	).Aria("sort", sortOrder)
+}
+
+func (t *dataTable[T]) renderRow(index int, labels DataTableLabels) app.UI {
+	row := t.Irows[index]
+	selected := t.selected[t.rowKey(index)]
+
+	return app.Tr().
+		Aria("selected", selected).
+		Body(
+			app.If(t.Iselectable, func() app.UI {
+				return app.Td().Body(
+					app.Input().
+						Type("checkbox").
+						Aria("label", labels.SelectRow).
+						Checked(selected).
+						OnChange(func(ctx app.Context, e app.Event) {
+							t.selectRow(ctx, index, e.Get("target").Get("checked").Bool())
+						}),
+				)
+			}),
+			app.Range(t.Icolumns).Slice(func(i int) app.UI {
+				return app.Td().
+					Style("overflow", "hidden").
+					Style("text-overflow", "ellipsis").
+					Body(t.Icolumns[i].column().renderCell(row))
+			}),
+		)
+}
+
+func (t *dataTable[T]) renderPagination(labels DataTableLabels) app.UI {
+	start := t.page*t.IpageSize + 1
+
+	return app.Nav().
+		Aria("label", labels.Pagination).
+		Style("margin-top", pxToString(BaseVPadding)).
+		Body(
+			Stack().
+				Middle().
+				Content(
+					app.Button().
+						Type("button").
+						Aria("label", labels.PreviousPage).
+						Disabled(t.page == 0).
+						OnClick(func(ctx app.Context, e app.Event) {
+							t.goToPage(t.page - 1)
+						}).
+						Text("‹"),
+					app.Span().
+						Aria("live", "polite").
+						Style("margin", "0 "+pxToString(BaseVPadding)).
+						Text(labels.Range(start, start+len(t.view)-1, t.filteredCount())),
+					app.Button().
+						Type("button").
+						Aria("label", labels.NextPage).
+						Disabled(t.page >= t.pages-1).
+						OnClick(func(ctx app.Context, e app.Event) {
+							t.goToPage(t.page + 1)
+						}).
+						Text("›"),
+				),
+		)
+}
+
+func (t *dataTable[T]) columnCount() int {
+	if t.Iselectable {
+		return len(t.Icolumns) + 1
+	}
+	return len(t.Icolumns)
+}
+
+func (t *dataTable[T]) columnWidth(i int) int {
+	c := t.Icolumns[i].column()
+	if w, ok := t.widths[c.id]; ok {
+		return w
+	}
+	return c.width
+}
+
+func (t *dataTable[T]) tableWidth() int {
+	width := 0
+	if t.Iselectable {
+		width += selectionColumnWidth
+	}
+	for i := range t.Icolumns {
+		width += t.columnWidth(i)
+	}
+	return width
+}
+
+func (t *dataTable[T]) filteredCount() int {
+	if strings.TrimSpace(t.query) == "" {
+		return len(t.Irows)
+	}
+
+	query := strings.ToLower(strings.TrimSpace(t.query))
+	count := 0
+	for _, row := range t.Irows {
+		if t.matches(row, query) {
+			count++
+		}
+	}
+	return count
+}
+
+func (t *dataTable[T]) onSearch(ctx app.Context, e app.Event) {
+	t.query = ctx.JSSrc().Get("value").String()
+	t.page = 0
+	t.refresh()
+}
+
+func (t *dataTable[T]) sort(column int) {
+	switch {
+	case t.sortColumn != column:
+		t.sortColumn = column
+		t.sortDesc = false
+
+	case !t.sortDesc:
+		t.sortDesc = true
+
+	default:
+		t.sortColumn = -1
+		t.sortDesc = false
+	}
+	t.refresh()
+}
+
+func (t *dataTable[T]) goToPage(page int) {
+	t.page = page
+	t.refresh()
+}
+
+func (t *dataTable[T]) allSelected() bool {
+	if len(t.view) == 0 {
+		return false
+	}
+
+	for _, index := range t.view {
+		if !t.selected[t.rowKey(index)] {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *dataTable[T]) onSelectAll(ctx app.Context, e app.Event) {
+	checked := e.Get("target").Get("checked").Bool()
+	for _, index := range t.view {
+		t.setSelected(index, checked)
+	}
+	t.notifySelection(ctx)
+}
+
+func (t *dataTable[T]) selectRow(ctx app.Context, index int, selected bool) {
+	t.setSelected(index, selected)
+	t.notifySelection(ctx)
+}
+
+func (t *dataTable[T]) setSelected(index int, selected bool) {
+	if t.selected == nil {
+		t.selected = make(map[string]bool)
+	}
+
+	if selected {
+		t.selected[t.rowKey(index)] = true
+	} else {
+		delete(t.selected, t.rowKey(index))
+	}
+}
+
+func (t *dataTable[T]) rowKey(index int) string {
+	if t.IrowKey != nil {
+		return t.IrowKey(t.Irows[index])
+	}
+	return strconv.Itoa(index)
+}
+
+func (t *dataTable[T]) notifySelection(ctx app.Context) {
+	if t.IonSelect == nil {
+		return
+	}
+
+	rows := make([]T, 0, len(t.selected))
+	for i, row := range t.Irows {
+		if t.selected[t.rowKey(i)] {
+			rows = append(rows, row)
+		}
+	}
+	t.IonSelect(ctx, rows)
+}
+
+func (t *dataTable[T]) startResize(e app.Event, column int) {
+	e.PreventDefault()
+	e.Get("target").Call("setPointerCapture", e.Get("pointerId"))
+
+	t.resizing = column
+	t.resizeX = e.Get("clientX").Int()
+	t.resizeFrom = t.columnWidth(column)
+}
+
+func (t *dataTable[T]) resize(ctx app.Context, e app.Event) {
+	if t.resizing < 0 {
+		ctx.PreventUpdate()
+		return
+	}
+
+	if t.resizing >= len(t.Icolumns) {
+		t.resizing = -1
+		return
+	}
+
+	if t.widths == nil {
+		t.widths = make(map[string]int)
+	}
+	width := t.resizeFrom + e.Get("clientX").Int() - t.resizeX
+	t.widths[t.Icolumns[t.resizing].column().id] = max(width, minColumnWidth)
+}
+
+func (t *dataTable[T]) endResize(ctx app.Context, e app.Event) {
+	t.resizing = -1
+}
+
+// sameRows reports whether the given slices refer to the same rows.
+func sameRows[T any](a, b []T) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
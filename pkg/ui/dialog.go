@@ -0,0 +1,200 @@
+package ui
+
+import (
+	"github.com/maxence-charriere/go-app/v11/pkg/app"
+)
+
+// IDialog is the interface that describes a dialog box displayed above the
+// page content.
+//
+// Dialogs are modal by default: the focus is trapped within the dialog and the
+// rest of the page is inert until it is closed. Dialogs are closed with the
+// escape key, and the focus is given back to the element that had it before
+// the dialog was opened. Dialogs opened from another dialog are stacked above
+// it.
+type IDialog interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IDialog
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IDialog
+
+	// Sets whether the dialog is displayed. Default is false.
+	Open(v bool) IDialog
+
+	// Sets whether the rest of the page remains interactive while the dialog
+	// is displayed. Default is false.
+	NonModal(v bool) IDialog
+
+	// Sets whether the dialog is closed by a click outside of its content.
+	// Default is false.
+	LightDismiss(v bool) IDialog
+
+	// Sets the accessible name of the dialog.
+	Label(v string) IDialog
+
+	// Sets the function called when the dialog is closed by the user, with
+	// the escape key or a click outside of its content. It should set the
+	// dialog back to not open.
+	OnClose(h app.EventHandler) IDialog
+
+	// Sets the content.
+	Content(v ...app.UI) IDialog
+}
+
+// Dialog returns a dialog box displayed above the page content.
+func Dialog() IDialog {
+	return &dialog{}
+}
+
+type dialog struct {
+	app.Compo
+
+	Iid           string
+	Iclass        string
+	Iopen         bool
+	InonModal     bool
+	IlightDismiss bool
+	Ilabel        string
+	IonClose      app.EventHandler
+	Icontent      []app.UI
+
+	lastFocus app.Value
+}
+
+func (d *dialog) ID(v string) IDialog {
+	d.Iid = v
+	return d
+}
+
+func (d *dialog) Class(v string) IDialog {
+	d.Iclass = app.AppendClass(d.Iclass, v)
+	return d
+}
+
+func (d *dialog) Open(v bool) IDialog {
+	d.Iopen = v
+	return d
+}
+
+func (d *dialog) NonModal(v bool) IDialog {
+	d.InonModal = v
+	return d
+}
+
+func (d *dialog) LightDismiss(v bool) IDialog {
+	d.IlightDismiss = v
+	return d
+}
+
+func (d *dialog) Label(v string) IDialog {
+	d.Ilabel = v
+	return d
+}
+
+func (d *dialog) OnClose(h app.EventHandler) IDialog {
+	d.IonClose = h
+	return d
+}
+
+func (d *dialog) Content(v ...app.UI) IDialog {
+	d.Icontent = app.FilterUIElems(v...)
+	return d
+}
+
+func (d *dialog) OnMount(ctx app.Context) {
+	d.refresh(ctx)
+}
+
+func (d *dialog) OnUpdate(ctx app.Context) {
+	ctx.Defer(d.refresh)
+}
+
+func (d *dialog) OnDismount() {
+	d.restoreFocus()
+}
+
+func (d *dialog) Render() app.UI {
+	return app.Dialog().
+		DataSet("goapp-ui", "dialog").
+		ID(d.Iid).
+		Class(d.Iclass).
+		Aria("label", d.Ilabel).
+		Aria("modal", !d.InonModal).
+		Style("padding", "0").
+		Style("color", ColorText).
+		Style("background-color", ColorBackground).
+		Style("border", "1px solid "+ColorBorder).
+		On("close", d.onClose).
+		OnKeyDown(d.onKeyDown).
+		OnClick(d.onClick).
+		Body(
+			app.Div().
+				Style("padding", pxToString(BlockMobilePadding)).
+				Body(d.Icontent...),
+		)
+}
+
+// refresh shows or closes the dialog element to match the open state. Showing
+// is made with javascript since the open attribute does not make a dialog
+// modal.
+func (d *dialog) refresh(ctx app.Context) {
+	if app.IsServer {
+		return
+	}
+
+	dialog := d.JSValue()
+	if !dialog.Truthy() {
+		return
+	}
+
+	open := dialog.Get("open").Bool()
+	switch {
+	case d.Iopen && !open:
+		d.lastFocus = app.Window().Get("document").Get("activeElement")
+		if d.InonModal {
+			dialog.Call("show")
+		} else {
+			dialog.Call("showModal")
+		}
+
+	case !d.Iopen && open:
+		dialog.Call("close")
+	}
+}
+
+func (d *dialog) onClose(ctx app.Context, e app.Event) {
+	d.restoreFocus()
+	if d.Iopen && d.IonClose != nil {
+		d.IonClose(ctx, e)
+	}
+}
+
+func (d *dialog) onKeyDown(ctx app.Context, e app.Event) {
+	// Modal dialogs are natively closed with the escape key.
+	if d.InonModal && e.Get("key").String() == "Escape" {
+		e.PreventDefault()
+		d.JSValue().Call("close")
+	}
+}
+
+func (d *dialog) onClick(ctx app.Context, e app.Event) {
+	// Clicks on the backdrop target the dialog element since its content fills
+	// it.
+	if d.IlightDismiss && e.Get("target").Equal(d.JSValue()) {
+		d.JSValue().Call("close")
+	}
+}
+
+func (d *dialog) restoreFocus() {
+	if d.lastFocus == nil {
+		return
+	}
+
+	if d.lastFocus.Truthy() {
+		d.lastFocus.Call("focus")
+	}
+	d.lastFocus = nil
+}
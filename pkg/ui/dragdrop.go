@@ -0,0 +1,922 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/maxence-charriere/go-app/v11/pkg/app"
+)
+
+const (
+	// The distance in px a pointer moves before a sortable item is dragged.
+	sortableDragThreshold = 4
+
+	dragDropState = "/goapp/ui/dragdrop"
+)
+
+// dragDrop holds the drag and drop operation in progress and the mounted drop
+// zones. It is kept in a state in order to be specific to an app, and is only
+// accessed from the UI goroutine.
+type dragDrop struct {
+	current *dragSession
+	zones   map[string]dropTarget
+}
+
+// dragDropOf returns the drag and drop state of the app that owns the given
+// context.
+func dragDropOf(ctx app.Context) *dragDrop {
+	var d *dragDrop
+	ctx.GetState(dragDropState, &d)
+	if d == nil {
+		d = &dragDrop{zones: make(map[string]dropTarget)}
+		ctx.SetState(dragDropState, d)
+	}
+	return d
+}
+
+type dragSession struct {
+	source  app.Value
+	payload any
+}
+
+type dropTarget interface {
+	accepts(payload any) bool
+	drop(payload any)
+	highlight(state string)
+}
+
+// start begins a drag and drop operation with the given payload and
+// highlights the drop zones that accept it.
+func (d *dragDrop) start(source app.Value, payload any) {
+	d.end()
+
+	d.current = &dragSession{
+		source:  source,
+		payload: payload,
+	}
+	source.Call("setAttribute", "aria-pressed", "true")
+	source.Get("style").Set("opacity", "0.5")
+
+	for _, z := range d.zones {
+		if z.accepts(payload) {
+			z.highlight("active")
+		}
+	}
+}
+
+// end ends the drag and drop operation in progress.
+func (d *dragDrop) end() {
+	if d.current == nil {
+		return
+	}
+
+	d.current.source.Call("setAttribute", "aria-pressed", "false")
+	d.current.source.Get("style").Set("opacity", "")
+	d.current = nil
+
+	for _, z := range d.zones {
+		z.highlight("")
+	}
+}
+
+// drop drops the payload of the drag and drop operation in progress into the
+// given drop zone. It reports whether the drop zone accepted it.
+func (d *dragDrop) drop(z dropTarget) bool {
+	if !d.accepted(z) {
+		return false
+	}
+
+	payload := d.current.payload
+	d.end()
+	z.drop(payload)
+	return true
+}
+
+// accepted reports whether the payload of the drag and drop operation in
+// progress can be dropped into the given drop zone.
+func (d *dragDrop) accepted(z dropTarget) bool {
+	return d.current != nil && z.accepts(d.current.payload)
+}
+
+// dragging reports whether the given element is being dragged.
+func (d *dragDrop) dragging(source app.Value) bool {
+	return d.current != nil && d.current.source.Equal(source)
+}
+
+// targetAt returns the drop zone displayed at the given viewport coordinates.
+func (d *dragDrop) targetAt(x, y int) dropTarget {
+	elem := app.Window().Get("document").Call("elementFromPoint", x, y)
+	if !elem.Truthy() {
+		return nil
+	}
+
+	zone := elem.Call("closest", "[data-goapp-dropzone]")
+	if !zone.Truthy() {
+		return nil
+	}
+	return d.zones[zone.Get("dataset").Get("goappDropzone").String()]
+}
+
+// IDraggable is the interface that describes an element that carries a
+// payload of type T that can be dropped into a drop zone.
+//
+// Draggable elements are moved with the mouse, with touch and pen pointers,
+// and with the keyboard: the space or enter key grabs the element, which is
+// then dropped with the same keys on a focused drop zone. The escape key
+// cancels the operation.
+type IDraggable[T any] interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IDraggable[T]
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IDraggable[T]
+
+	// Sets the value carried to drop zones.
+	Payload(v T) IDraggable[T]
+
+	// Sets the accessible name.
+	Label(v string) IDraggable[T]
+
+	// Sets whether the element cannot be dragged. Default is false.
+	Disabled(v bool) IDraggable[T]
+
+	// Sets the content.
+	Content(v ...app.UI) IDraggable[T]
+}
+
+// Draggable creates an element that carries a payload of type T that can be
+// dropped into a drop zone.
+func Draggable[T any]() IDraggable[T] {
+	return &draggable[T]{}
+}
+
+type draggable[T any] struct {
+	app.Compo
+
+	Iid       string
+	Iclass    string
+	Ipayload  T
+	Ilabel    string
+	Idisabled bool
+	Icontent  []app.UI
+
+	dnd  *dragDrop
+	over dropTarget
+}
+
+func (d *draggable[T]) ID(v string) IDraggable[T] {
+	d.Iid = v
+	return d
+}
+
+func (d *draggable[T]) Class(v string) IDraggable[T] {
+	d.Iclass = app.AppendClass(d.Iclass, v)
+	return d
+}
+
+func (d *draggable[T]) Payload(v T) IDraggable[T] {
+	d.Ipayload = v
+	return d
+}
+
+func (d *draggable[T]) Label(v string) IDraggable[T] {
+	d.Ilabel = v
+	return d
+}
+
+func (d *draggable[T]) Disabled(v bool) IDraggable[T] {
+	d.Idisabled = v
+	return d
+}
+
+func (d *draggable[T]) Content(v ...app.UI) IDraggable[T] {
+	d.Icontent = app.FilterUIElems(v...)
+	return d
+}
+
+func (d *draggable[T]) OnMount(ctx app.Context) {
+	d.dnd = dragDropOf(ctx)
+}
+
+func (d *draggable[T]) OnDismount() {
+	if d.dnd != nil && d.dnd.dragging(d.JSValue()) {
+		d.dnd.end()
+	}
+}
+
+func (d *draggable[T]) Render() app.UI {
+	cursor := "grab"
+	if d.Idisabled {
+		cursor = "default"
+	}
+
+	return app.Div().
+		DataSet("goapp-ui", "draggable").
+		ID(d.Iid).
+		Class(d.Iclass).
+		Draggable(!d.Idisabled).
+		TabIndex(0).
+		Role("button").
+		Aria("roledescription", "draggable").
+		Aria("label", d.Ilabel).
+		Aria("disabled", d.Idisabled).
+		Style("cursor", cursor).
+		Style("touch-action", "none").
+		Style("user-select", "none").
+		OnDragStart(d.onDragStart).
+		OnDragEnd(d.onDragEnd).
+		On("pointerdown", d.onPointerDown).
+		On("pointermove", d.onPointerMove).
+		On("pointerup", d.onPointerUp).
+		On("pointercancel", d.onDragEnd).
+		OnKeyDown(d.onKeyDown).
+		Body(d.Icontent...)
+}
+
+func (d *draggable[T]) onDragStart(ctx app.Context, e app.Event) {
+	ctx.PreventUpdate()
+	if d.Idisabled {
+		e.PreventDefault()
+		return
+	}
+
+	dataTransfer := e.Get("dataTransfer")
+	dataTransfer.Call("setData", "text/plain", d.Ilabel)
+	dataTransfer.Set("effectAllowed", "move")
+	d.dnd.start(d.JSValue(), d.Ipayload)
+}
+
+func (d *draggable[T]) onDragEnd(ctx app.Context, e app.Event) {
+	ctx.PreventUpdate()
+	d.over = nil
+	d.dnd.end()
+}
+
+// onPointerDown starts a drag with touch and pen pointers, which do not
+// consistently support native drag and drop. Mouse drags are native.
+func (d *draggable[T]) onPointerDown(ctx app.Context, e app.Event) {
+	ctx.PreventUpdate()
+	if d.Idisabled || e.Get("pointerType").String() == "mouse" {
+		return
+	}
+
+	e.Get("target").Call("setPointerCapture", e.Get("pointerId"))
+	d.dnd.start(d.JSValue(), d.Ipayload)
+}
+
+func (d *draggable[T]) onPointerMove(ctx app.Context, e app.Event) {
+	ctx.PreventUpdate()
+	if d.dnd.current == nil || e.Get("pointerType").String() == "mouse" {
+		return
+	}
+
+	over := d.dnd.targetAt(e.Get("clientX").Int(), e.Get("clientY").Int())
+	if over == d.over {
+		return
+	}
+
+	if d.over != nil {
+		d.over.highlight("active")
+	}
+	d.over = nil
+	if over != nil && d.dnd.accepted(over) {
+		over.highlight("over")
+		d.over = over
+	}
+}
+
+func (d *draggable[T]) onPointerUp(ctx app.Context, e app.Event) {
+	ctx.PreventUpdate()
+	if d.dnd.current == nil || e.Get("pointerType").String() == "mouse" {
+		return
+	}
+
+	if d.over != nil {
+		d.dnd.drop(d.over)
+	}
+	d.over = nil
+	d.dnd.end()
+}
+
+func (d *draggable[T]) onKeyDown(ctx app.Context, e app.Event) {
+	ctx.PreventUpdate()
+	if d.Idisabled {
+		return
+	}
+
+	switch e.Get("key").String() {
+	case " ", "Enter":
+		e.PreventDefault()
+		if d.dnd.dragging(d.JSValue()) {
+			d.dnd.end()
+			return
+		}
+		d.dnd.start(d.JSValue(), d.Ipayload)
+
+	case "Escape":
+		d.dnd.end()
+	}
+}
+
+// IDropZone is the interface that describes an element that receives the
+// payloads of type T of draggable elements.
+//
+// While a compatible element is dragged, the drop zone has its
+// data-goapp-drop attribute set to "active", or to "over" when the element is
+// above it, which can be used to style it.
+type IDropZone[T any] interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IDropZone[T]
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IDropZone[T]
+
+	// Sets the accessible name.
+	Label(v string) IDropZone[T]
+
+	// Sets the function that reports whether a payload can be dropped. All
+	// the payloads of type T are accepted when it is not set.
+	Accept(f func(v T) bool) IDropZone[T]
+
+	// Sets the function called on the UI goroutine when a payload is
+	// dropped.
+	OnDrop(h func(ctx app.Context, v T)) IDropZone[T]
+
+	// Sets the content.
+	Content(v ...app.UI) IDropZone[T]
+}
+
+// DropZone creates an element that receives the payloads of type T of
+// draggable elements.
+func DropZone[T any]() IDropZone[T] {
+	return &dropZone[T]{
+		id: "goapp-dropzone-" + uuid.NewString(),
+	}
+}
+
+type dropZone[T any] struct {
+	app.Compo
+
+	Iid      string
+	Iclass   string
+	Ilabel   string
+	Iaccept  func(T) bool
+	IonDrop  func(app.Context, T)
+	Icontent []app.UI
+
+	id  string
+	ctx app.Context
+	dnd *dragDrop
+}
+
+func (z *dropZone[T]) ID(v string) IDropZone[T] {
+	z.Iid = v
+	return z
+}
+
+func (z *dropZone[T]) Class(v string) IDropZone[T] {
+	z.Iclass = app.AppendClass(z.Iclass, v)
+	return z
+}
+
+func (z *dropZone[T]) Label(v string) IDropZone[T] {
+	z.Ilabel = v
+	return z
+}
+
+func (z *dropZone[T]) Accept(f func(T) bool) IDropZone[T] {
+	z.Iaccept = f
+	return z
+}
+
+func (z *dropZone[T]) OnDrop(h func(app.Context, T)) IDropZone[T] {
+	z.IonDrop = h
+	return z
+}
+
+func (z *dropZone[T]) Content(v ...app.UI) IDropZone[T] {
+	z.Icontent = app.FilterUIElems(v...)
+	return z
+}
+
+func (z *dropZone[T]) OnMount(ctx app.Context) {
+	z.ctx = ctx
+	z.dnd = dragDropOf(ctx)
+	z.dnd.zones[z.id] = z
+	if z.dnd.accepted(z) {
+		z.highlight("active")
+	}
+}
+
+func (z *dropZone[T]) OnDismount() {
+	if z.dnd != nil {
+		delete(z.dnd.zones, z.id)
+	}
+}
+
+func (z *dropZone[T]) Render() app.UI {
+	return app.Div().
+		DataSet("goapp-ui", "drop-zone").
+		DataSet("goapp-dropzone", z.id).
+		ID(z.Iid).
+		Class(z.Iclass).
+		TabIndex(0).
+		Role("region").
+		Aria("label", z.Ilabel).
+		OnDragEnter(z.onDragOver).
+		OnDragOver(z.onDragOver).
+		OnDragLeave(z.onDragLeave).
+		OnDrop(z.onDrop).
+		OnKeyDown(z.onKeyDown).
+		Body(z.Icontent...)
+}
+
+func (z *dropZone[T]) accepts(payload any) bool {
+	v, ok := payload.(T)
+	if !ok {
+		return false
+	}
+	return z.Iaccept == nil || z.Iaccept(v)
+}
+
+func (z *dropZone[T]) drop(payload any) {
+	v := payload.(T)
+	z.ctx.Dispatch(func(ctx app.Context) {
+		if z.IonDrop != nil {
+			z.IonDrop(ctx, v)
+		}
+	})
+}
+
+func (z *dropZone[T]) highlight(state string) {
+	elem := z.JSValue()
+	if elem == nil || !elem.Truthy() {
+		return
+	}
+
+	var outline string
+	switch state {
+	case "active":
+		outline = "2px dashed " + ColorPrimary
+	case "over":
+		outline = "2px solid " + ColorPrimary
+	}
+
+	elem.Get("dataset").Set("goappDrop", state)
+	elem.Get("style").Set("outline", outline)
+}
+
+func (z *dropZone[T]) onDragOver(ctx app.Context, e app.Event) {
+	ctx.PreventUpdate()
+	if !z.dnd.accepted(z) {
+		return
+	}
+
+	e.PreventDefault()
+	e.Get("dataTransfer").Set("dropEffect", "move")
+	z.highlight("over")
+}
+
+func (z *dropZone[T]) onDragLeave(ctx app.Context, e app.Event) {
+	ctx.PreventUpdate()
+	if !z.dnd.accepted(z) {
+		return
+	}
+
+	if related := e.Get("relatedTarget"); related.Truthy() && z.JSValue().Call("contains", related).Bool() {
+		return
+	}
+	z.highlight("active")
+}
+
+func (z *dropZone[T]) onDrop(ctx app.Context, e app.Event) {
+	ctx.PreventUpdate()
+	e.PreventDefault()
+	z.dnd.drop(z)
+}
+
+func (z *dropZone[T]) onKeyDown(ctx app.Context, e app.Event) {
+	ctx.PreventUpdate()
+	switch e.Get("key").String() {
+	case " ", "Enter":
+		if z.dnd.drop(z) {
+			e.PreventDefault()
+		}
+
+	case "Escape":
+		z.dnd.end()
+	}
+}
+
+// ISortableList is the interface that describes a list of items of type T that
+// can be reordered.
+//
+// Items are moved with the mouse, touch and pen pointers, and with the
+// keyboard: the space or enter key grabs the focused item, the arrow keys move
+// it, and the space or enter key drops it. The escape key puts a grabbed item
+// back to its original position. Moves are announced to assistive
+// technologies.
+//
+// A pointer drag starts once the pointer moved by a few pixels, which keeps the
+// clicks on the buttons, links and fields within the items working.
+type ISortableList[T any] interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) ISortableList[T]
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) ISortableList[T]
+
+	// Sets the accessible name.
+	Label(v string) ISortableList[T]
+
+	// Sets the items.
+	Items(v ...T) ISortableList[T]
+
+	// Sets the function that renders an item.
+	Item(f func(i int, v T) app.UI) ISortableList[T]
+
+	// Sets the texts announced to assistive technologies. Unset texts are
+	// announced in English.
+	Announcements(v SortableAnnouncements) ISortableList[T]
+
+	// Sets the function called on the UI goroutine with the reordered items
+	// when an item is moved. It should update the items of the list.
+	OnReorder(h func(ctx app.Context, items []T)) ISortableList[T]
+}
+
+// SortableAnnouncements contains the texts that a sortable list announces to
+// assistive technologies. Functions are called with the position of the item,
+// starting at 1, and the number of items.
+type SortableAnnouncements struct {
+	// The description of the item role. Default is "sortable item".
+	ItemDescription string
+
+	// Returns the text announced when an item is grabbed with the keyboard.
+	Grabbed func(position, count int) string
+
+	// Returns the text announced when an item is moved.
+	Moved func(position, count int) string
+
+	// Returns the text announced when an item grabbed with the keyboard is
+	// dropped.
+	Dropped func(position, count int) string
+
+	// Returns the text announced when a keyboard move is cancelled.
+	Cancelled func(position, count int) string
+}
+
+func (a SortableAnnouncements) withDefaults() SortableAnnouncements {
+	if a.ItemDescription == "" {
+		a.ItemDescription = "sortable item"
+	}
+	if a.Grabbed == nil {
+		a.Grabbed = func(position, count int) string {
+			return fmt.Sprintf("Item grabbed at position %v of %v. Use the arrow keys to move it, space to drop it, or escape to cancel.", position, count)
+		}
+	}
+	if a.Moved == nil {
+		a.Moved = func(position, count int) string {
+			return fmt.Sprintf("Item moved to position %v of %v.", position, count)
+		}
+	}
+	if a.Dropped == nil {
+		a.Dropped = func(position, count int) string {
+			return fmt.Sprintf("Item dropped at position %v of %v.", position, count)
+		}
+	}
+	if a.Cancelled == nil {
+		a.Cancelled = func(position, count int) string {
+			return fmt.Sprintf("Move cancelled. Item returned to position %v of %v.", position, count)
+		}
+	}
+	return a
+}
+
+// SortableList creates a list of items of type T that can be reordered.
+func SortableList[T any]() ISortableList[T] {
+	return &sortableList[T]{
+		id:       "goapp-sortable-" + uuid.NewString(),
+		pressed:  -1,
+		dragging: -1,
+		over:     -1,
+		grabbed:  -1,
+	}
+}
+
+type sortableList[T any] struct {
+	app.Compo
+
+	Iid            string
+	Iclass         string
+	Ilabel         string
+	Iitems         []T
+	Iitem          func(int, T) app.UI
+	Iannouncements SortableAnnouncements
+	IonReorder     func(app.Context, []T)
+
+	id           string
+	pressed      int
+	pressX       int
+	pressY       int
+	pressTarget  app.Value
+	dragging     int
+	over         int
+	grabbed      int
+	grabbedFrom  int
+	announcement string
+}
+
+func (l *sortableList[T]) ID(v string) ISortableList[T] {
+	l.Iid = v
+	return l
+}
+
+func (l *sortableList[T]) Class(v string) ISortableList[T] {
+	l.Iclass = app.AppendClass(l.Iclass, v)
+	return l
+}
+
+func (l *sortableList[T]) Label(v string) ISortableList[T] {
+	l.Ilabel = v
+	return l
+}
+
+func (l *sortableList[T]) Items(v ...T) ISortableList[T] {
+	l.Iitems = v
+	return l
+}
+
+func (l *sortableList[T]) Item(f func(int, T) app.UI) ISortableList[T] {
+	l.Iitem = f
+	return l
+}
+
+func (l *sortableList[T]) Announcements(v SortableAnnouncements) ISortableList[T] {
+	l.Iannouncements = v
+	return l
+}
+
+func (l *sortableList[T]) OnReorder(h func(app.Context, []T)) ISortableList[T] {
+	l.IonReorder = h
+	return l
+}
+
+func (l *sortableList[T]) OnUpdate(ctx app.Context) {
+	if l.grabbed >= len(l.Iitems) {
+		l.grabbed = -1
+	}
+	if l.dragging >= len(l.Iitems) {
+		l.dragging = -1
+		l.over = -1
+	}
+	if l.pressed >= len(l.Iitems) {
+		l.pressed = -1
+	}
+}
+
+func (l *sortableList[T]) Render() app.UI {
+	return app.Div().
+		DataSet("goapp-ui", "sortable-list").
+		ID(l.Iid).
+		Class(l.Iclass).
+		Body(
+			app.Ul().
+				ID(l.id).
+				Aria("label", l.Ilabel).
+				Style("list-style", "none").
+				Style("margin", "0").
+				Style("padding", "0").
+				Body(
+					app.Range(l.Iitems).Slice(func(i int) app.UI {
+						return l.renderItem(i)
+					}),
+				),
+			app.Div().
+				Aria("live", "assertive").
+				Style("position", "absolute").
+				Style("width", "1px").
+				Style("height", "1px").
+				Style("overflow", "hidden").
+				Style("clip-path", "inset(50%)").
+				Style("white-space", "nowrap").
+				Text(l.announcement),
+		)
+}
+
+func (l *sortableList[T]) renderItem(i int) app.UI {
+	var content app.UI
+	if l.Iitem != nil {
+		content = l.Iitem(i, l.Iitems[i])
+	}
+
+	item := app.Li().
+		DataSet("goapp-sortable-index", i).
+		TabIndex(0).
+		Aria("roledescription", l.Iannouncements.withDefaults().ItemDescription).
+		Aria("pressed", l.grabbed == i).
+		Style("cursor", "grab").
+		Style("touch-action", "none").
+		Style("user-select", "none").
+		Style("outline-offset", "-2px").
+		On("pointerdown", func(ctx app.Context, e app.Event) {
+			l.startDrag(ctx, e, i)
+		}).
+		On("pointermove", l.drag).
+		On("pointerup", l.endDrag).
+		On("pointercancel", l.cancelDrag).
+		OnKeyDown(func(ctx app.Context, e app.Event) {
+			l.onKeyDown(ctx, e, i)
+		}).
+		Body(content)
+
+	switch {
+	case l.grabbed == i, l.dragging == i:
+		item.Style("background-color", ColorSurface)
+
+	case l.over == i && l.dragging >= 0 && l.over < l.dragging:
+		item.Style("box-shadow", "inset 0 2px 0 "+ColorPrimary)
+
+	case l.over == i && l.dragging >= 0 && l.over > l.dragging:
+		item.Style("box-shadow", "inset 0 -2px 0 "+ColorPrimary)
+	}
+	return item
+}
+
+// startDrag records the pressed item. The drag starts once the pointer moved
+// beyond sortableDragThreshold, so that a click on an element within the item
+// is not captured.
+func (l *sortableList[T]) startDrag(ctx app.Context, e app.Event, index int) {
+	ctx.PreventUpdate()
+	if e.Get("pointerType").String() == "mouse" && e.Get("button").Int() != 0 {
+		return
+	}
+
+	l.pressed = index
+	l.pressX = e.Get("clientX").Int()
+	l.pressY = e.Get("clientY").Int()
+	l.pressTarget = e.Get("currentTarget")
+}
+
+func (l *sortableList[T]) drag(ctx app.Context, e app.Event) {
+	if l.pressed >= 0 {
+		dx := e.Get("clientX").Int() - l.pressX
+		dy := e.Get("clientY").Int() - l.pressY
+		if dx*dx+dy*dy < sortableDragThreshold*sortableDragThreshold {
+			ctx.PreventUpdate()
+			return
+		}
+
+		l.pressTarget.Call("setPointerCapture", e.Get("pointerId"))
+		l.dragging = l.pressed
+		l.over = l.pressed
+		l.pressed = -1
+		l.pressTarget = nil
+	}
+
+	if l.dragging < 0 {
+		ctx.PreventUpdate()
+		return
+	}
+
+	over := l.indexAt(e.Get("clientX").Int(), e.Get("clientY").Int())
+	if over < 0 || over == l.over {
+		ctx.PreventUpdate()
+		return
+	}
+	l.over = over
+}
+
+func (l *sortableList[T]) endDrag(ctx app.Context, e app.Event) {
+	l.pressed = -1
+	l.pressTarget = nil
+	if l.dragging < 0 {
+		ctx.PreventUpdate()
+		return
+	}
+
+	from, to := l.dragging, l.over
+	l.dragging = -1
+	l.over = -1
+	if to >= 0 && to != from {
+		l.move(ctx, from, to)
+		l.announce(l.Iannouncements.withDefaults().Moved(to+1, len(l.Iitems)))
+	}
+}
+
+func (l *sortableList[T]) cancelDrag(ctx app.Context, e app.Event) {
+	l.pressed = -1
+	l.pressTarget = nil
+	l.dragging = -1
+	l.over = -1
+}
+
+// indexAt returns the index of the item displayed at the given viewport
+// coordinates, or -1 when there is none.
+func (l *sortableList[T]) indexAt(x, y int) int {
+	elem := app.Window().Get("document").Call("elementFromPoint", x, y)
+	if !elem.Truthy() {
+		return -1
+	}
+
+	item := elem.Call("closest", "[data-goapp-sortable-index]")
+	if !item.Truthy() || item.Get("parentElement").Get("id").String() != l.id {
+		return -1
+	}
+
+	index, err := strconv.Atoi(item.Get("dataset").Get("goappSortableIndex").String())
+	if err != nil {
+		return -1
+	}
+	return index
+}
+
+func (l *sortableList[T]) onKeyDown(ctx app.Context, e app.Event, index int) {
+	switch e.Get("key").String() {
+	case " ", "Enter":
+		e.PreventDefault()
+		if l.grabbed == index {
+			l.grabbed = -1
+			l.announce(l.Iannouncements.withDefaults().Dropped(index+1, len(l.Iitems)))
+			return
+		}
+		l.grabbed = index
+		l.grabbedFrom = index
+		l.announce(l.Iannouncements.withDefaults().Grabbed(index+1, len(l.Iitems)))
+
+	case "ArrowUp", "ArrowDown":
+		e.PreventDefault()
+		to := index + 1
+		if e.Get("key").String() == "ArrowUp" {
+			to = index - 1
+		}
+		if to < 0 || to >= len(l.Iitems) {
+			ctx.PreventUpdate()
+			return
+		}
+
+		if l.grabbed == index {
+			l.move(ctx, index, to)
+			l.grabbed = to
+			l.announce(l.Iannouncements.withDefaults().Moved(to+1, len(l.Iitems)))
+		}
+		ctx.Defer(func(ctx app.Context) {
+			l.focusItem(to)
+		})
+
+	case "Escape":
+		if l.grabbed < 0 {
+			ctx.PreventUpdate()
+			return
+		}
+
+		from, to := l.grabbed, l.grabbedFrom
+		l.grabbed = -1
+		if from != to {
+			l.move(ctx, from, to)
+			ctx.Defer(func(ctx app.Context) {
+				l.focusItem(to)
+			})
+		}
+		l.announce(l.Iannouncements.withDefaults().Cancelled(to+1, len(l.Iitems)))
+
+	default:
+		ctx.PreventUpdate()
+	}
+}
+
+// move moves the item at the given index to another index and notifies the
+// new order.
+func (l *sortableList[T]) move(ctx app.Context, from, to int) {
+	items := slices.Clone(l.Iitems)
+	item := items[from]
+	items = slices.Delete(items, from, from+1)
+	items = slices.Insert(items, to, item)
+	l.Iitems = items
+
+	if l.IonReorder != nil {
+		l.IonReorder(ctx, items)
+	}
+}
+
+func (l *sortableList[T]) focusItem(index int) {
+	list := app.Window().GetElementByID(l.id)
+	if !list.Truthy() {
+		return
+	}
+
+	if item := list.Get("children").Index(index); item.Truthy() {
+		item.Call("focus")
+	}
+}
+
+func (l *sortableList[T]) announce(v string) {
+	l.announcement = v
+}
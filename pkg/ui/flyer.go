@@ -184,6 +184,13 @@ func (f *flyer) Render() app.UI {
 }
 
 func (f *flyer) resize(ctx app.Context) {
+	theme := CurrentTheme(ctx)
+	f.hpadding = BaseAdHPadding
+	if theme.BaseHPadding > 0 {
+		f.hpadding = theme.BaseHPadding / 2
+	}
+	f.vpadding = themeOrDefault(theme.BaseVPadding, BaseVPadding)
+
 	if app.IsServer {
 		return
 	}
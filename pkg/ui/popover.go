@@ -0,0 +1,236 @@
+package ui
+
+import (
+	"github.com/google/uuid"
+	"github.com/maxence-charriere/go-app/v11/pkg/app"
+)
+
+// IPopover is the interface that describes a content displayed above the page
+// next to the element that toggles it.
+//
+// Popovers are closed with the escape key or with a click outside of their
+// content. Popovers opened from another popover are stacked above it, opening
+// a popover closing the ones that are not its ancestors.
+type IPopover interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IPopover
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IPopover
+
+	// Sets the element that toggles the popover when clicked.
+	Anchor(v app.UI) IPopover
+
+	// Sets the accessible name of the popover content.
+	Label(v string) IPopover
+
+	// Sets the space in px between the anchor and the popover content.
+	// Default is 6px.
+	Offset(px int) IPopover
+
+	// Sets the content.
+	Content(v ...app.UI) IPopover
+}
+
+// Popover returns a content displayed above the page next to the element that
+// toggles it.
+func Popover() IPopover {
+	return newPopover("dialog")
+}
+
+// Menu returns a popover that displays a list of actions. Menu items are
+// elements with the "menuitem" role. They can be browsed with the arrow keys,
+// and the menu is closed when one of them is clicked.
+func Menu() IPopover {
+	return newPopover("menu")
+}
+
+func newPopover(role string) *popover {
+	return &popover{
+		Ioffset: DefaultIconSpace,
+		role:    role,
+		id:      "goapp-popover-" + uuid.NewString(),
+	}
+}
+
+type popover struct {
+	app.Compo
+
+	Iid      string
+	Iclass   string
+	Ianchor  app.UI
+	Ilabel   string
+	Ioffset  int
+	Icontent []app.UI
+
+	role string
+	id   string
+	open bool
+}
+
+func (p *popover) ID(v string) IPopover {
+	p.Iid = v
+	return p
+}
+
+func (p *popover) Class(v string) IPopover {
+	p.Iclass = app.AppendClass(p.Iclass, v)
+	return p
+}
+
+func (p *popover) Anchor(v app.UI) IPopover {
+	a := app.FilterUIElems(v)
+	if len(a) != 0 {
+		p.Ianchor = a[0]
+	}
+	return p
+}
+
+func (p *popover) Label(v string) IPopover {
+	p.Ilabel = v
+	return p
+}
+
+func (p *popover) Offset(px int) IPopover {
+	if px >= 0 {
+		p.Ioffset = px
+	}
+	return p
+}
+
+func (p *popover) Content(v ...app.UI) IPopover {
+	p.Icontent = app.FilterUIElems(v...)
+	return p
+}
+
+func (p *popover) Render() app.UI {
+	return app.Div().
+		DataSet("goapp-ui", "popover").
+		ID(p.Iid).
+		Class(p.Iclass).
+		Style("display", "inline-block").
+		Body(
+			app.Button().
+				ID(p.id+"-anchor").
+				Type("button").
+				Style("all", "unset").
+				Style("cursor", "pointer").
+				Attr("popovertarget", p.id).
+				Aria("haspopup", p.role).
+				Aria("expanded", p.open).
+				Aria("controls", p.id).
+				Body(p.Ianchor),
+			app.Div().
+				ID(p.id).
+				Attr("popover", "auto").
+				Role(p.role).
+				Aria("label", p.Ilabel).
+				Style("position", "fixed").
+				Style("margin", "0").
+				Style("inset", "auto").
+				Style("color", ColorText).
+				Style("background-color", ColorSurface).
+				Style("border", "1px solid "+ColorBorder).
+				On("beforetoggle", p.onBeforeToggle).
+				OnKeyDown(p.onKeyDown).
+				OnClick(p.onClick).
+				Body(p.Icontent...),
+		)
+}
+
+func (p *popover) onBeforeToggle(ctx app.Context, e app.Event) {
+	p.open = e.Get("newState").String() == "open"
+	if !p.open {
+		return
+	}
+
+	p.place()
+	if p.role == "menu" {
+		ctx.Defer(func(ctx app.Context) {
+			p.focusMenuItem(0)
+		})
+	}
+}
+
+// place positions the popover content under the anchor, or above it when
+// there is not enough space below.
+func (p *popover) place() {
+	anchor := app.Window().GetElementByID(p.id + "-anchor")
+	content := app.Window().GetElementByID(p.id)
+	if !anchor.Truthy() || !content.Truthy() {
+		return
+	}
+
+	_, windowHeight := app.Window().Size()
+	rect := anchor.Call("getBoundingClientRect")
+	contentHeight := content.Get("offsetHeight").Int()
+
+	top := rect.Get("bottom").Int() + p.Ioffset
+	if top+contentHeight > windowHeight {
+		top = max(rect.Get("top").Int()-p.Ioffset-contentHeight, 0)
+	}
+
+	style := content.Get("style")
+	style.Set("top", pxToString(top))
+	style.Set("left", pxToString(rect.Get("left").Int()))
+}
+
+func (p *popover) onKeyDown(ctx app.Context, e app.Event) {
+	if p.role != "menu" {
+		return
+	}
+
+	switch e.Get("key").String() {
+	case "ArrowDown":
+		e.PreventDefault()
+		p.focusMenuItem(1)
+
+	case "ArrowUp":
+		e.PreventDefault()
+		p.focusMenuItem(-1)
+
+	case "Home":
+		e.PreventDefault()
+		p.focusMenuItem(0)
+	}
+}
+
+// focusMenuItem moves the focus by the given number of menu items relative to
+// the focused one. The first item is focused when the move is 0 or when no
+// item has the focus.
+func (p *popover) focusMenuItem(move int) {
+	content := app.Window().GetElementByID(p.id)
+	if !content.Truthy() {
+		return
+	}
+
+	items := content.Call("querySelectorAll", `[role="menuitem"]`)
+	count := items.Length()
+	if count == 0 {
+		return
+	}
+
+	next := 0
+	if move != 0 {
+		active := app.Window().Get("document").Get("activeElement")
+		for i := 0; i < count; i++ {
+			if items.Index(i).Equal(active) {
+				next = ((i+move)%count + count) % count
+				break
+			}
+		}
+	}
+	items.Index(next).Call("focus")
+}
+
+func (p *popover) onClick(ctx app.Context, e app.Event) {
+	if p.role != "menu" {
+		return
+	}
+
+	if item := e.Get("target").Call("closest", `[role="menuitem"]`); item.Truthy() {
+		app.Window().GetElementByID(p.id).Call("hidePopover")
+	}
+}
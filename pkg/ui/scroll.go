@@ -140,11 +140,13 @@ func (s *scroll) Render() app.UI {
 
 func (s *scroll) resize(ctx app.Context) {
 	w, _ := ctx.Page().Size()
-	if w <= 480 {
-		s.hpadding = BaseMobileHPadding
+	theme := CurrentTheme(ctx)
+	if ctx.MatchMedia(mobileQuery) {
+		s.hpadding = themeOrDefault(theme.BaseMobileHPadding, BaseMobileHPadding)
 	} else {
-		s.hpadding = BaseHPadding
+		s.hpadding = themeOrDefault(theme.BaseHPadding, BaseHPadding)
 	}
+	s.vpadding = themeOrDefault(theme.BaseVPadding, BaseVPadding)
 
 	if w != s.width {
 		s.width = w
@@ -0,0 +1,350 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/maxence-charriere/go-app/v11/pkg/app"
+)
+
+const (
+	themeState = "/goapp/ui/theme"
+)
+
+// CSS values that refer to the theme tokens exposed by a ThemeProvider. They
+// can be used as style values, and fall back to system values when there is no
+// enclosing ThemeProvider.
+const (
+	ColorBackground    = "var(--goapp-color-background, Canvas)"
+	ColorSurface       = "var(--goapp-color-surface, Canvas)"
+	ColorText          = "var(--goapp-color-text, CanvasText)"
+	ColorSecondaryText = "var(--goapp-color-secondary-text, GrayText)"
+	ColorPrimary       = "var(--goapp-color-primary, AccentColor)"
+	ColorOnPrimary     = "var(--goapp-color-on-primary, AccentColorText)"
+	ColorBorder        = "var(--goapp-color-border, GrayText)"
+	ColorError         = "var(--goapp-color-error, red)"
+	FontFamily         = "var(--goapp-font-family, inherit)"
+	FontSize           = "var(--goapp-font-size, inherit)"
+	LineHeight         = "var(--goapp-line-height, inherit)"
+)
+
+// ColorScheme represents the color scheme of a theme.
+type ColorScheme string
+
+const (
+	// AutoColorScheme follows the color scheme preferred by the user, as
+	// reported by the prefers-color-scheme media query.
+	AutoColorScheme ColorScheme = ""
+
+	// LightColorScheme uses the light palette.
+	LightColorScheme ColorScheme = "light"
+
+	// DarkColorScheme uses the dark palette.
+	DarkColorScheme ColorScheme = "dark"
+)
+
+// Palette is a set of CSS colors.
+type Palette struct {
+	Background    string
+	Surface       string
+	Text          string
+	SecondaryText string
+	Primary       string
+	OnPrimary     string
+	Border        string
+	Error         string
+}
+
+// Theme describes the design tokens of the ui components: colors, typography
+// and spacing.
+type Theme struct {
+	// The color scheme. Default is AutoColorScheme.
+	ColorScheme ColorScheme
+
+	// The colors used with the light color scheme.
+	Light Palette
+
+	// The colors used with the dark color scheme.
+	Dark Palette
+
+	// The CSS font family.
+	FontFamily string
+
+	// The font size in px.
+	FontSize int
+
+	// The line height, relative to the font size.
+	LineHeight float64
+
+	// The padding of block-like components in px.
+	BlockPadding int
+
+	// The padding of block-like components in px when app width is <= 480px.
+	BlockMobilePadding int
+
+	// The content width of block-like components in px.
+	BlockContentWidth int
+
+	// The horizontal padding of base-like components in px.
+	BaseHPadding int
+
+	// The horizontal padding of base-like components in px when app width is
+	// <= 480px.
+	BaseMobileHPadding int
+
+	// The vertical padding of base-like components in px.
+	BaseVPadding int
+
+	// The icon size in px.
+	IconSize int
+
+	// The icon space in px.
+	IconSpace int
+}
+
+// DefaultTheme returns a theme made of the package-level sizes, such as
+// BlockPadding or BaseHPadding, and of neutral light and dark palettes.
+func DefaultTheme() Theme {
+	return Theme{
+		Light: Palette{
+			Background:    "#ffffff",
+			Surface:       "#f4f4f5",
+			Text:          "#18181b",
+			SecondaryText: "#52525b",
+			Primary:       "#2563eb",
+			OnPrimary:     "#ffffff",
+			Border:        "#d4d4d8",
+			Error:         "#dc2626",
+		},
+		Dark: Palette{
+			Background:    "#18181b",
+			Surface:       "#27272a",
+			Text:          "#fafafa",
+			SecondaryText: "#a1a1aa",
+			Primary:       "#60a5fa",
+			OnPrimary:     "#0b1220",
+			Border:        "#3f3f46",
+			Error:         "#f87171",
+		},
+		FontFamily:         "system-ui, sans-serif",
+		FontSize:           16,
+		LineHeight:         1.5,
+		BlockPadding:       BlockPadding,
+		BlockMobilePadding: BlockMobilePadding,
+		BlockContentWidth:  BlockContentWidth,
+		BaseHPadding:       BaseHPadding,
+		BaseMobileHPadding: BaseMobileHPadding,
+		BaseVPadding:       BaseVPadding,
+		IconSize:           DefaultIconSize,
+		IconSpace:          DefaultIconSpace,
+	}
+}
+
+// SetTheme sets the theme of the app. Theme providers are rendered again and
+// the components within them are resized with the new spacing.
+func SetTheme(ctx app.Context, t Theme) {
+	ctx.SetState(themeState, t)
+}
+
+// SetColorScheme changes the color scheme of the current theme.
+func SetColorScheme(ctx app.Context, v ColorScheme) {
+	t := CurrentTheme(ctx)
+	t.ColorScheme = v
+	SetTheme(ctx, t)
+}
+
+// CurrentTheme returns the theme of the app. It returns the theme set with
+// SetTheme, or the theme of the closest ThemeProvider that encloses the
+// component that owns the given context when no theme has been set, or the
+// default theme otherwise.
+func CurrentTheme(ctx app.Context) Theme {
+	if p, ok := app.Ancestor[*themeProvider](ctx.Src()); ok {
+		return p.resolveTheme(ctx)
+	}
+
+	t := DefaultTheme()
+	ctx.GetState(themeState, &t)
+	return t
+}
+
+// IThemeProvider is the interface that describes a container that exposes the
+// theme tokens to its content as CSS custom properties.
+//
+// The palette matching the color scheme is applied, the automatic color scheme
+// following the prefers-color-scheme media query without rendering again. The
+// provider is rendered again when the theme is changed with SetTheme or
+// SetColorScheme.
+//
+// Spacing tokens are exposed in px as --goapp-spacing-block-padding,
+// --goapp-spacing-block-mobile-padding, --goapp-spacing-block-content-width,
+// --goapp-spacing-base-h-padding, --goapp-spacing-base-mobile-h-padding,
+// --goapp-spacing-base-v-padding, --goapp-spacing-icon-size and
+// --goapp-spacing-icon-space.
+type IThemeProvider interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IThemeProvider
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IThemeProvider
+
+	// Sets the theme used when no theme has been set with SetTheme. It is
+	// returned by CurrentTheme to the components within the provider. Default
+	// is DefaultTheme().
+	Theme(v Theme) IThemeProvider
+
+	// Sets the content.
+	Content(v ...app.UI) IThemeProvider
+}
+
+// ThemeProvider creates a container that exposes the theme tokens to its
+// content as CSS custom properties.
+func ThemeProvider() IThemeProvider {
+	return &themeProvider{}
+}
+
+type themeProvider struct {
+	app.Compo
+
+	Iid      string
+	Iclass   string
+	Itheme   *Theme
+	Icontent []app.UI
+
+	theme Theme
+}
+
+func (p *themeProvider) ID(v string) IThemeProvider {
+	p.Iid = v
+	return p
+}
+
+func (p *themeProvider) Class(v string) IThemeProvider {
+	p.Iclass = app.AppendClass(p.Iclass, v)
+	return p
+}
+
+func (p *themeProvider) Theme(v Theme) IThemeProvider {
+	p.Itheme = &v
+	return p
+}
+
+func (p *themeProvider) Content(v ...app.UI) IThemeProvider {
+	p.Icontent = app.FilterUIElems(v...)
+	return p
+}
+
+func (p *themeProvider) OnPreRender(ctx app.Context) {
+	p.observe(ctx)
+}
+
+func (p *themeProvider) OnMount(ctx app.Context) {
+	p.observe(ctx)
+}
+
+func (p *themeProvider) OnUpdate(ctx app.Context) {
+	if t := p.resolveTheme(ctx); t != p.theme {
+		p.theme = t
+		ctx.ResizeContent()
+	}
+}
+
+func (p *themeProvider) observe(ctx app.Context) {
+	p.theme = p.resolveTheme(ctx)
+	ctx.ObserveState(themeState, &p.theme).
+		OnChange(ctx.ResizeContent)
+}
+
+// resolveTheme returns the theme set with SetTheme, or the provider theme when
+// no theme has been set.
+func (p *themeProvider) resolveTheme(ctx app.Context) Theme {
+	t := DefaultTheme()
+	if p.Itheme != nil {
+		t = *p.Itheme
+	}
+	ctx.GetState(themeState, &t)
+	return t
+}
+
+func (p *themeProvider) Render() app.UI {
+	t := p.theme
+
+	colorScheme := "light dark"
+	if t.ColorScheme != AutoColorScheme {
+		colorScheme = string(t.ColorScheme)
+	}
+
+	root := app.Div().
+		DataSet("goapp-ui", "theme-provider").
+		ID(p.Iid).
+		Class(p.Iclass).
+		Style("width", "100%").
+		Style("height", "100%").
+		Style("color-scheme", colorScheme).
+		Style("font-family", FontFamily).
+		Style("font-size", FontSize).
+		Style("line-height", LineHeight).
+		Style("color", ColorText).
+		Style("background-color", ColorBackground)
+
+	colors := []struct {
+		name  string
+		light string
+		dark  string
+	}{
+		{name: "background", light: t.Light.Background, dark: t.Dark.Background},
+		{name: "surface", light: t.Light.Surface, dark: t.Dark.Surface},
+		{name: "text", light: t.Light.Text, dark: t.Dark.Text},
+		{name: "secondary-text", light: t.Light.SecondaryText, dark: t.Dark.SecondaryText},
+		{name: "primary", light: t.Light.Primary, dark: t.Dark.Primary},
+		{name: "on-primary", light: t.Light.OnPrimary, dark: t.Dark.OnPrimary},
+		{name: "border", light: t.Light.Border, dark: t.Dark.Border},
+		{name: "error", light: t.Light.Error, dark: t.Dark.Error},
+	}
+	for _, c := range colors {
+		if c.light == "" || c.dark == "" {
+			continue
+		}
+		root.Style("--goapp-color-"+c.name, fmt.Sprintf("light-dark(%s, %s)", c.light, c.dark))
+	}
+
+	if t.FontFamily != "" {
+		root.Style("--goapp-font-family", t.FontFamily)
+	}
+	if t.FontSize > 0 {
+		root.Style("--goapp-font-size", pxToString(t.FontSize))
+	}
+	if t.LineHeight > 0 {
+		root.Style("--goapp-line-height", strconv.FormatFloat(t.LineHeight, 'f', -1, 64))
+	}
+
+	spacings := []struct {
+		name         string
+		value        int
+		defaultValue int
+	}{
+		{name: "block-padding", value: t.BlockPadding, defaultValue: BlockPadding},
+		{name: "block-mobile-padding", value: t.BlockMobilePadding, defaultValue: BlockMobilePadding},
+		{name: "block-content-width", value: t.BlockContentWidth, defaultValue: BlockContentWidth},
+		{name: "base-h-padding", value: t.BaseHPadding, defaultValue: BaseHPadding},
+		{name: "base-mobile-h-padding", value: t.BaseMobileHPadding, defaultValue: BaseMobileHPadding},
+		{name: "base-v-padding", value: t.BaseVPadding, defaultValue: BaseVPadding},
+		{name: "icon-size", value: t.IconSize, defaultValue: DefaultIconSize},
+		{name: "icon-space", value: t.IconSpace, defaultValue: DefaultIconSpace},
+	}
+	for _, s := range spacings {
+		root.Style("--goapp-spacing-"+s.name, pxToString(themeOrDefault(s.value, s.defaultValue)))
+	}
+
+	return root.Body(p.Icontent...)
+}
+
+// themeOrDefault returns the given value when it is greater than 0, or the
+// default value otherwise.
+func themeOrDefault(v, defaultValue int) int {
+	if v > 0 {
+		return v
+	}
+	return defaultValue
+}
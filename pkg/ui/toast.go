@@ -0,0 +1,383 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/maxence-charriere/go-app/v11/pkg/app"
+)
+
+const (
+	defaultToastDuration = 5 * time.Second
+)
+
+// IToast is the interface that describes a brief message displayed above the
+// page content, such as a snackbar.
+//
+// Toasts are announced by screen readers and dismissed once their duration
+// elapsed. The dismissal is paused while the toast is hovered or has the focus.
+type IToast interface {
+	app.UI
+
+	// Sets the ID. Toasts within a toaster should have a unique ID: the toaster
+	// keeps the remaining time of a toast by its ID, and a toast whose ID is
+	// new is scheduled with its full duration.
+	ID(v string) IToast
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IToast
+
+	// Sets the duration after which the toast is dismissed. A duration less
+	// than or equal to 0 keeps the toast until it is dismissed by the user.
+	// Default is 5s.
+	Duration(v time.Duration) IToast
+
+	// Sets whether the toast is an alert that screen readers announce
+	// immediately. Default is false.
+	Alert(v bool) IToast
+
+	// Sets the elements, such as an undo button, displayed next to the
+	// content.
+	Action(v ...app.UI) IToast
+
+	// Sets the accessible label of a close button that dismisses the toast.
+	// The close button is displayed only when a label is set.
+	CloseLabel(v string) IToast
+
+	// Sets the function called when the toast is dismissed. It should remove
+	// the toast from its toaster.
+	OnDismiss(h func(app.Context)) IToast
+
+	// Sets the content.
+	Content(v ...app.UI) IToast
+}
+
+// Toast returns a brief message displayed above the page content. Toasts are
+// intended to be displayed within a Toaster.
+func Toast() IToast {
+	return &toast{
+		Iduration: defaultToastDuration,
+	}
+}
+
+type toast struct {
+	app.Compo
+
+	Iid         string
+	Iclass      string
+	Iduration   time.Duration
+	Ialert      bool
+	Iaction     []app.UI
+	IcloseLabel string
+	IonDismiss  func(app.Context)
+	Icontent    []app.UI
+
+	id        string
+	timing    *toastTiming
+	timer     app.Timer
+	dismissed bool
+}
+
+func (t *toast) ID(v string) IToast {
+	t.Iid = v
+	return t
+}
+
+func (t *toast) Class(v string) IToast {
+	t.Iclass = app.AppendClass(t.Iclass, v)
+	return t
+}
+
+func (t *toast) Duration(v time.Duration) IToast {
+	t.Iduration = v
+	return t
+}
+
+func (t *toast) Alert(v bool) IToast {
+	t.Ialert = v
+	return t
+}
+
+func (t *toast) Action(v ...app.UI) IToast {
+	t.Iaction = app.FilterUIElems(v...)
+	return t
+}
+
+func (t *toast) CloseLabel(v string) IToast {
+	t.IcloseLabel = v
+	return t
+}
+
+func (t *toast) OnDismiss(h func(app.Context)) IToast {
+	t.IonDismiss = h
+	return t
+}
+
+func (t *toast) Content(v ...app.UI) IToast {
+	t.Icontent = app.FilterUIElems(v...)
+	return t
+}
+
+func (t *toast) OnMount(ctx app.Context) {
+	t.id = t.Iid
+	t.timing = t.resolveTiming(ctx)
+	t.schedule(ctx)
+}
+
+func (t *toast) OnUpdate(ctx app.Context) {
+	if t.Iid == t.id {
+		return
+	}
+
+	t.id = t.Iid
+	t.timer.Stop()
+	t.dismissed = false
+	t.timing = t.resolveTiming(ctx)
+	t.schedule(ctx)
+}
+
+// resolveTiming returns the timing of the toast, shared by its enclosing
+// toaster in order to be kept when the toast is displayed by another component
+// after the toasts before it are removed.
+func (t *toast) resolveTiming(ctx app.Context) *toastTiming {
+	if toaster, ok := app.Ancestor[*toaster](ctx.Src()); ok && t.Iid != "" {
+		return toaster.timing(t.Iid, t.Iduration)
+	}
+	return &toastTiming{remaining: t.Iduration}
+}
+
+func (t *toast) Render() app.UI {
+	role := "status"
+	live := "polite"
+	if t.Ialert {
+		role = "alert"
+		live = "assertive"
+	}
+
+	return app.Div().
+		DataSet("goapp-ui", "toast").
+		ID(t.Iid).
+		Class(t.Iclass).
+		Role(role).
+		Aria("live", live).
+		Aria("atomic", true).
+		Hidden(t.dismissed).
+		Style("padding", pxToString(BaseVPadding)).
+		Style("color", ColorText).
+		Style("background-color", ColorSurface).
+		Style("border", "1px solid "+ColorBorder).
+		OnMouseEnter(t.pause).
+		OnMouseLeave(t.resume).
+		On("focusin", t.pause).
+		On("focusout", t.resume).
+		Body(
+			Stack().
+				Middle().
+				Content(
+					app.Div().
+						Style("flex-grow", "1").
+						Body(t.Icontent...),
+					app.If(len(t.Iaction) != 0, func() app.UI {
+						return app.Div().
+							Style("margin-left", pxToString(BlockMobilePadding)).
+							Body(t.Iaction...)
+					}),
+					app.If(t.IcloseLabel != "", func() app.UI {
+						return app.Button().
+							Type("button").
+							Aria("label", t.IcloseLabel).
+							Style("margin-left", pxToString(BlockMobilePadding)).
+							Style("color", "inherit").
+							Style("background", "none").
+							Style("border", "none").
+							Style("cursor", "pointer").
+							OnClick(t.close).
+							Body(
+								app.Span().
+									Aria("hidden", true).
+									Text("×"),
+							)
+					}),
+				),
+		)
+}
+
+func (t *toast) schedule(ctx app.Context) {
+	if t.Iduration <= 0 || t.dismissed || t.timing == nil {
+		return
+	}
+	t.timer = ctx.AfterFunc(t.timing.resume(time.Now()), t.dismiss)
+}
+
+func (t *toast) pause(ctx app.Context, e app.Event) {
+	t.timer.Stop()
+	if t.timing != nil {
+		t.timing.pause(time.Now())
+	}
+}
+
+func (t *toast) resume(ctx app.Context, e app.Event) {
+	t.timer.Stop()
+	t.schedule(ctx)
+}
+
+func (t *toast) close(ctx app.Context, e app.Event) {
+	t.timer.Stop()
+	t.dismiss(ctx)
+}
+
+func (t *toast) dismiss(ctx app.Context) {
+	t.dismissed = true
+	if t.IonDismiss != nil {
+		t.IonDismiss(ctx)
+	}
+}
+
+// toastTiming tracks the time remaining before a toast is dismissed.
+type toastTiming struct {
+	remaining time.Duration
+	running   bool
+	start     time.Time
+}
+
+// resume starts the countdown when it is not running and returns the time
+// remaining before the toast is dismissed.
+func (t *toastTiming) resume(now time.Time) time.Duration {
+	if !t.running {
+		t.running = true
+		t.start = now
+	}
+	return max(t.remaining-now.Sub(t.start), 0)
+}
+
+// pause stops the countdown and keeps the remaining time.
+func (t *toastTiming) pause(now time.Time) {
+	if !t.running {
+		return
+	}
+	t.remaining = max(t.remaining-now.Sub(t.start), 0)
+	t.running = false
+}
+
+// IToaster is the interface that describes a container that stacks toasts
+// above the page content.
+type IToaster interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IToaster
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IToaster
+
+	// Sets the accessible label of the toaster. Default is "Notifications".
+	Label(v string) IToaster
+
+	// Sets the maximum number of displayed toasts, the oldest ones being hidden
+	// first. Default is 3.
+	Max(v int) IToaster
+
+	// Sets the toasts, from the oldest to the most recent. Toasts are
+	// identified by their ID across updates.
+	Toasts(v ...app.UI) IToaster
+}
+
+// Toaster returns a container that stacks toasts at the bottom of the page,
+// above its content.
+func Toaster() IToaster {
+	return &toaster{
+		Ilabel: "Notifications",
+		Imax:   3,
+	}
+}
+
+type toaster struct {
+	app.Compo
+
+	Iid     string
+	Iclass  string
+	Ilabel  string
+	Imax    int
+	Itoasts []app.UI
+
+	timings map[string]*toastTiming
+}
+
+func (t *toaster) ID(v string) IToaster {
+	t.Iid = v
+	return t
+}
+
+func (t *toaster) Class(v string) IToaster {
+	t.Iclass = app.AppendClass(t.Iclass, v)
+	return t
+}
+
+func (t *toaster) Label(v string) IToaster {
+	t.Ilabel = v
+	return t
+}
+
+func (t *toaster) Max(v int) IToaster {
+	if v > 0 {
+		t.Imax = v
+	}
+	return t
+}
+
+func (t *toaster) Toasts(v ...app.UI) IToaster {
+	t.Itoasts = app.FilterUIElems(v...)
+	return t
+}
+
+func (t *toaster) OnUpdate(ctx app.Context) {
+	ids := make(map[string]bool, len(t.Itoasts))
+	for _, v := range t.Itoasts {
+		if toast, ok := v.(*toast); ok {
+			ids[toast.Iid] = true
+		}
+	}
+
+	for id := range t.timings {
+		if !ids[id] {
+			delete(t.timings, id)
+		}
+	}
+}
+
+// timing returns the timing of the toast with the given ID, created with the
+// given duration when the toast is new.
+func (t *toaster) timing(id string, d time.Duration) *toastTiming {
+	if t.timings == nil {
+		t.timings = make(map[string]*toastTiming)
+	}
+
+	timing, ok := t.timings[id]
+	if !ok {
+		timing = &toastTiming{remaining: d}
+		t.timings[id] = timing
+	}
+	return timing
+}
+
+func (t *toaster) Render() app.UI {
+	toasts := t.Itoasts
+	if len(toasts) > t.Imax {
+		toasts = toasts[len(toasts)-t.Imax:]
+	}
+
+	return app.Section().
+		DataSet("goapp-ui", "toaster").
+		ID(t.Iid).
+		Class(t.Iclass).
+		Aria("label", t.Ilabel).
+		Style("position", "fixed").
+		Style("left", "50%").
+		Style("bottom", pxToString(BaseVPadding)).
+		Style("transform", "translateX(-50%)").
+		Style("z-index", "2147483647").
+		Style("display", "flex").
+		Style("flex-direction", "column-reverse").
+		Style("gap", pxToString(BaseVPadding)).
+		Style("max-width", "calc(100vw - "+pxToString(BaseMobileHPadding*2)+")").
+		Body(toasts...)
+}
@@ -39,6 +39,9 @@ var (
 
 const (
 	defaultHeaderHeight = 90
+
+	// The media query matched when app width is <= 480px.
+	mobileQuery = "(max-width: 480px)"
 )
 
 func pxToString(px int) string {
@@ -0,0 +1,649 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/maxence-charriere/go-app/v11/pkg/app"
+)
+
+const (
+	defaultVirtualItemHeight = 48
+	defaultVirtualOverscan   = 5
+	defaultVirtualViewport   = 1080
+)
+
+// IVirtualList is the interface that describes a list that only renders the
+// items that are visible within its scroll container.
+//
+// The scroll container is the closest ancestor with a scrollable overflow,
+// such as the content of a Scroll, or the page when there is none. Items can
+// have different heights: they are measured once rendered, the estimated item
+// height being used for the items that have not been rendered yet.
+//
+// Measured heights are kept by item key when a key function is set, and are
+// reset when the number of items changes otherwise.
+type IVirtualList interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IVirtualList
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IVirtualList
+
+	// Sets the number of items.
+	Count(v int) IVirtualList
+
+	// Sets the estimated height in px of an item. Default is 48px.
+	ItemHeight(px int) IVirtualList
+
+	// Sets the number of items rendered before and after the visible ones.
+	// Default is 5.
+	Overscan(v int) IVirtualList
+
+	// Sets the function that renders the item at the given index.
+	Item(f func(i int) app.UI) IVirtualList
+
+	// Sets the function that returns the key identifying the item at the
+	// given index. It allows to keep the measured heights of the items when
+	// they are reordered or filtered.
+	ItemKey(f func(i int) string) IVirtualList
+}
+
+// VirtualList creates a list that only renders the items that are visible
+// within its scroll container.
+func VirtualList() IVirtualList {
+	return &virtualList{
+		IitemHeight: defaultVirtualItemHeight,
+		Ioverscan:   defaultVirtualOverscan,
+		state:       newVirtualState("goapp-virtual-list-"),
+	}
+}
+
+type virtualList struct {
+	app.Compo
+
+	Iid         string
+	Iclass      string
+	Icount      int
+	IitemHeight int
+	Ioverscan   int
+	Iitem       func(int) app.UI
+	IitemKey    func(int) string
+
+	state virtualState
+}
+
+func (l *virtualList) ID(v string) IVirtualList {
+	l.Iid = v
+	return l
+}
+
+func (l *virtualList) Class(v string) IVirtualList {
+	l.Iclass = app.AppendClass(l.Iclass, v)
+	return l
+}
+
+func (l *virtualList) Count(v int) IVirtualList {
+	l.Icount = max(v, 0)
+	return l
+}
+
+func (l *virtualList) ItemHeight(px int) IVirtualList {
+	if px > 0 {
+		l.IitemHeight = px
+	}
+	return l
+}
+
+func (l *virtualList) Overscan(v int) IVirtualList {
+	if v >= 0 {
+		l.Ioverscan = v
+	}
+	return l
+}
+
+func (l *virtualList) Item(f func(int) app.UI) IVirtualList {
+	l.Iitem = f
+	return l
+}
+
+func (l *virtualList) ItemKey(f func(int) string) IVirtualList {
+	l.IitemKey = f
+	return l
+}
+
+func (l *virtualList) OnMount(ctx app.Context) {
+	l.state.mount(ctx, l.refresh)
+}
+
+func (l *virtualList) OnDismount() {
+	l.state.dismount()
+}
+
+func (l *virtualList) OnResize(ctx app.Context) {
+	l.refresh(ctx)
+}
+
+func (l *virtualList) OnUpdate(ctx app.Context) {
+	l.state.invalidate()
+	ctx.Defer(l.refresh)
+}
+
+func (l *virtualList) Render() app.UI {
+	l.state.layout(l.Icount, l.IitemHeight, l.IitemKey)
+	return l.state.render("virtual-list", l.Iid, l.Iclass, l.Ioverscan, func(row int) app.UI {
+		if l.Iitem == nil {
+			return nil
+		}
+		return l.Iitem(row)
+	})
+}
+
+func (l *virtualList) refresh(ctx app.Context) {
+	l.state.layout(l.Icount, l.IitemHeight, l.IitemKey)
+	if l.state.refresh(l.Ioverscan) {
+		ctx.Update()
+	}
+}
+
+// IVirtualGrid is the interface that describes a grid that only renders the
+// rows of items that are visible within its scroll container.
+//
+// The number of columns is determined by the grid width and the item width.
+// The scroll container and the row heights are handled the same way as for a
+// VirtualList, a row being identified by the keys of its items.
+type IVirtualGrid interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IVirtualGrid
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IVirtualGrid
+
+	// Sets the number of items.
+	Count(v int) IVirtualGrid
+
+	// Sets the minimum width in px of an item. Default is 372px.
+	ItemWidth(px int) IVirtualGrid
+
+	// Sets the estimated height in px of an item. Default is 48px.
+	ItemHeight(px int) IVirtualGrid
+
+	// Sets the space between items in px.
+	Spacing(px int) IVirtualGrid
+
+	// Sets the number of rows rendered before and after the visible ones.
+	// Default is 5.
+	Overscan(v int) IVirtualGrid
+
+	// Sets the function that renders the item at the given index.
+	Item(f func(i int) app.UI) IVirtualGrid
+
+	// Sets the function that returns the key identifying the item at the
+	// given index. It allows to keep the measured heights of the rows when the
+	// items are reordered or filtered.
+	ItemKey(f func(i int) string) IVirtualGrid
+}
+
+// VirtualGrid creates a grid that only renders the rows of items that are
+// visible within its scroll container.
+func VirtualGrid() IVirtualGrid {
+	return &virtualGrid{
+		IitemWidth:  DefaultFlowItemWidth,
+		IitemHeight: defaultVirtualItemHeight,
+		Ioverscan:   defaultVirtualOverscan,
+		state:       newVirtualState("goapp-virtual-grid-"),
+	}
+}
+
+type virtualGrid struct {
+	app.Compo
+
+	Iid         string
+	Iclass      string
+	Icount      int
+	IitemWidth  int
+	IitemHeight int
+	Ispacing    int
+	Ioverscan   int
+	Iitem       func(int) app.UI
+	IitemKey    func(int) string
+
+	state virtualState
+}
+
+func (g *virtualGrid) ID(v string) IVirtualGrid {
+	g.Iid = v
+	return g
+}
+
+func (g *virtualGrid) Class(v string) IVirtualGrid {
+	g.Iclass = app.AppendClass(g.Iclass, v)
+	return g
+}
+
+func (g *virtualGrid) Count(v int) IVirtualGrid {
+	g.Icount = max(v, 0)
+	return g
+}
+
+func (g *virtualGrid) ItemWidth(px int) IVirtualGrid {
+	if px > 0 {
+		g.IitemWidth = px
+	}
+	return g
+}
+
+func (g *virtualGrid) ItemHeight(px int) IVirtualGrid {
+	if px > 0 {
+		g.IitemHeight = px
+	}
+	return g
+}
+
+func (g *virtualGrid) Spacing(px int) IVirtualGrid {
+	if px >= 0 {
+		g.Ispacing = px
+	}
+	return g
+}
+
+func (g *virtualGrid) Overscan(v int) IVirtualGrid {
+	if v >= 0 {
+		g.Ioverscan = v
+	}
+	return g
+}
+
+func (g *virtualGrid) Item(f func(int) app.UI) IVirtualGrid {
+	g.Iitem = f
+	return g
+}
+
+func (g *virtualGrid) ItemKey(f func(int) string) IVirtualGrid {
+	g.IitemKey = f
+	return g
+}
+
+func (g *virtualGrid) OnMount(ctx app.Context) {
+	g.state.mount(ctx, g.refresh)
+}
+
+func (g *virtualGrid) OnDismount() {
+	g.state.dismount()
+}
+
+func (g *virtualGrid) OnResize(ctx app.Context) {
+	g.refresh(ctx)
+}
+
+func (g *virtualGrid) OnUpdate(ctx app.Context) {
+	g.state.invalidate()
+	ctx.Defer(g.refresh)
+}
+
+func (g *virtualGrid) Render() app.UI {
+	columns := g.state.columns
+	itemWidth := fmt.Sprintf("calc((100%% - %vpx) / %v)", (columns-1)*g.Ispacing, columns)
+
+	g.layout()
+	return g.state.render("virtual-grid", g.Iid, g.Iclass, g.Ioverscan, func(row int) app.UI {
+		start := row * columns
+		end := min(start+columns, g.Icount)
+
+		return app.Div().
+			Style("display", "flex").
+			Style("gap", pxToString(g.Ispacing)).
+			Style("padding-bottom", pxToString(g.Ispacing)).
+			Body(
+				app.Range(make([]struct{}, end-start)).Slice(func(i int) app.UI {
+					var item app.UI
+					if g.Iitem != nil {
+						item = g.Iitem(start + i)
+					}
+
+					return app.Div().
+						Style("flex", "0 0 "+itemWidth).
+						Style("min-width", "0").
+						Body(item)
+				}),
+			)
+	})
+}
+
+func (g *virtualGrid) refresh(ctx app.Context) {
+	changed := g.state.refreshColumns(g.IitemWidth, g.Ispacing)
+
+	g.layout()
+	if g.state.refresh(g.Ioverscan) || changed {
+		ctx.Update()
+	}
+}
+
+// layout sets the rows of the grid, identified by the keys of their items.
+func (g *virtualGrid) layout() {
+	columns := g.state.columns
+	rows := (g.Icount + columns - 1) / columns
+
+	var rowKey func(int) string
+	if g.IitemKey != nil {
+		rowKey = func(row int) string {
+			start := row * columns
+			end := min(start+columns, g.Icount)
+
+			keys := make([]string, 0, end-start)
+			for i := start; i < end; i++ {
+				keys = append(keys, g.IitemKey(i))
+			}
+			return strings.Join(keys, "\x00")
+		}
+	}
+	g.state.layout(rows, g.IitemHeight+g.Ispacing, rowKey)
+}
+
+// virtualState holds the scroll position and the row measurements of a
+// virtualized component.
+type virtualState struct {
+	id        string
+	heights   map[string]int
+	offsets   virtualOffsets
+	rowKey    func(int) string
+	dirty     bool
+	first     int
+	last      int
+	columns   int
+	mounted   bool
+	container app.Value
+	onScroll  app.Func
+}
+
+func newVirtualState(idPrefix string) virtualState {
+	return virtualState{
+		id:      idPrefix + uuid.NewString(),
+		heights: make(map[string]int),
+		last:    -1,
+		columns: 1,
+	}
+}
+
+// invalidate reports that the rows may have changed. Their offsets are
+// computed again from their keys on the next layout.
+func (s *virtualState) invalidate() {
+	s.dirty = true
+}
+
+// layout sets the number of rows, their estimated height and the function
+// that returns the key identifying a row. Row offsets are computed again when
+// they changed, with the measured heights of the rows identified by the same
+// keys. Without key function, rows are identified by their index and the
+// measured heights are reset when the number of rows changes.
+func (s *virtualState) layout(rows, estimate int, rowKey func(int) string) {
+	keyed := rowKey != nil
+	if !keyed {
+		rowKey = strconv.Itoa
+	}
+	s.rowKey = rowKey
+
+	sameRows := rows == s.offsets.rows() && estimate == s.offsets.estimate
+	if sameRows && (!s.dirty || !keyed) {
+		s.dirty = false
+		return
+	}
+	s.dirty = false
+
+	if !keyed && rows != s.offsets.rows() {
+		s.heights = make(map[string]int)
+	}
+
+	s.offsets.reset(rows, estimate)
+	if !keyed {
+		for key, height := range s.heights {
+			if row, err := strconv.Atoi(key); err == nil && row < rows {
+				s.offsets.set(row, height)
+			}
+		}
+		return
+	}
+
+	heights := make(map[string]int, len(s.heights))
+	for row := 0; row < rows; row++ {
+		key := rowKey(row)
+		if height, ok := s.heights[key]; ok {
+			heights[key] = height
+			s.offsets.set(row, height)
+		}
+	}
+	s.heights = heights
+}
+
+// mount listens to the scroll events of the closest scroll container and
+// calls the given refresh function when they occur.
+func (s *virtualState) mount(ctx app.Context, refresh func(app.Context)) {
+	if app.IsServer {
+		return
+	}
+
+	element := app.Window().GetElementByID(s.id)
+	if !element.Truthy() {
+		return
+	}
+
+	s.container = scrollContainer(element)
+	s.onScroll = app.FuncOf(func(this app.Value, args []app.Value) any {
+		ctx.Dispatch(func(ctx app.Context) {
+			ctx.PreventUpdate()
+			refresh(ctx)
+		})
+		return nil
+	})
+	s.container.Call("addEventListener", "scroll", s.onScroll, map[string]any{
+		"passive": true,
+	})
+	s.mounted = true
+	refresh(ctx)
+}
+
+func (s *virtualState) dismount() {
+	if s.onScroll == nil {
+		return
+	}
+
+	s.container.Call("removeEventListener", "scroll", s.onScroll)
+	s.onScroll.Release()
+	s.onScroll = nil
+	s.container = nil
+	s.mounted = false
+}
+
+// refreshColumns sets the number of columns that fit within the component
+// width. It reports whether the number of columns changed, which resets the
+// row measurements.
+func (s *virtualState) refreshColumns(itemWidth, spacing int) bool {
+	if !s.mounted {
+		return false
+	}
+
+	element := app.Window().GetElementByID(s.id)
+	if !element.Truthy() {
+		return false
+	}
+
+	width := element.Get("clientWidth").Int()
+	columns := max((width+spacing)/(itemWidth+spacing), 1)
+	if columns == s.columns {
+		return false
+	}
+
+	s.columns = columns
+	s.heights = make(map[string]int)
+	s.offsets.reset(0, 0)
+	return true
+}
+
+// refresh measures the rendered rows and computes the range of rows that are
+// visible within the scroll container. It reports whether the component must
+// be rendered again.
+func (s *virtualState) refresh(overscan int) bool {
+	if !s.mounted {
+		return false
+	}
+
+	element := app.Window().GetElementByID(s.id)
+	if !element.Truthy() {
+		return false
+	}
+
+	changed := false
+	rendered := element.Call("querySelectorAll", "[data-goapp-row]")
+	for i := 0; i < rendered.Length(); i++ {
+		row := rendered.Index(i)
+		index, err := strconv.Atoi(row.Call("getAttribute", "data-goapp-row").String())
+		if err != nil || index >= s.offsets.rows() {
+			continue
+		}
+
+		key := s.rowKey(index)
+		height := row.Get("offsetHeight").Int()
+		if measured, ok := s.heights[key]; !ok || measured != height {
+			s.heights[key] = height
+			s.offsets.set(index, height)
+			changed = true
+		}
+	}
+
+	viewTop := 0
+	viewHeight := app.Window().Get("innerHeight").Int()
+	if !s.container.Equal(app.Window()) {
+		viewTop = s.container.Call("getBoundingClientRect").Get("top").Int()
+		viewHeight = s.container.Get("clientHeight").Int()
+	}
+
+	top := element.Call("getBoundingClientRect").Get("top").Int()
+	start := max(viewTop-top, 0)
+	first, last := s.visibleRows(overscan, start, start+viewHeight)
+	if first != s.first || last != s.last {
+		s.first = first
+		s.last = last
+		changed = true
+	}
+	return changed
+}
+
+// visibleRows returns the range of rows, overscan included, that are between
+// the given start and end offsets in px. Rows are found with a binary search
+// on their offsets.
+func (s *virtualState) visibleRows(overscan, start, end int) (first, last int) {
+	rows := s.offsets.rows()
+	first = sort.Search(rows, func(row int) bool {
+		return s.offsets.offset(row+1) > start
+	})
+	last = sort.Search(rows, func(row int) bool {
+		return s.offsets.offset(row) >= end
+	})
+
+	first = max(first-overscan, 0)
+	last = min(last+overscan, rows)
+	return first, last
+}
+
+func (s *virtualState) render(name, id, class string, overscan int, renderRow func(row int) app.UI) app.UI {
+	rows := s.offsets.rows()
+	first, last := s.first, s.last
+	if !s.mounted || last < 0 {
+		first = 0
+		last = min(defaultVirtualViewport/max(s.offsets.estimate, 1)+overscan, rows)
+	}
+	first = min(first, rows)
+	last = min(last, rows)
+
+	return app.Div().
+		DataSet("goapp-ui", name).
+		ID(id).
+		Class(class).
+		Body(
+			app.Div().
+				ID(s.id).
+				Style("position", "relative").
+				Style("height", pxToString(s.offsets.offset(rows))).
+				Body(
+					app.Div().
+						Style("position", "absolute").
+						Style("top", "0").
+						Style("left", "0").
+						Style("right", "0").
+						Style("transform", fmt.Sprintf("translateY(%vpx)", s.offsets.offset(first))).
+						Body(
+							app.Range(make([]struct{}, last-first)).Slice(func(i int) app.UI {
+								row := first + i
+								return app.Div().
+									DataSet("goapp-row", row).
+									Body(renderRow(row))
+							}),
+						),
+				),
+		)
+}
+
+// virtualOffsets computes the offsets of rows whose heights are measured or
+// estimated. The differences between the measured heights and the estimated
+// one are kept in a Fenwick tree, which allows to update a row height and to
+// compute a row offset in O(log n).
+type virtualOffsets struct {
+	estimate int
+	deltas   []int
+	tree     []int
+}
+
+// reset sets the number of rows and their estimated height, and forgets the
+// measured heights.
+func (o *virtualOffsets) reset(rows, estimate int) {
+	o.estimate = estimate
+	o.deltas = make([]int, rows)
+	o.tree = make([]int, rows+1)
+}
+
+func (o *virtualOffsets) rows() int {
+	return len(o.deltas)
+}
+
+// set sets the measured height of the given row.
+func (o *virtualOffsets) set(row, height int) {
+	delta := height - o.estimate
+	diff := delta - o.deltas[row]
+	if diff == 0 {
+		return
+	}
+
+	o.deltas[row] = delta
+	for i := row + 1; i < len(o.tree); i += i & -i {
+		o.tree[i] += diff
+	}
+}
+
+// offset returns the offset in px of the given row.
+func (o *virtualOffsets) offset(row int) int {
+	offset := row * o.estimate
+	for i := min(row, o.rows()); i > 0; i -= i & -i {
+		offset += o.tree[i]
+	}
+	return offset
+}
+
+// scrollContainer returns the closest ancestor of the given element that has
+// a scrollable overflow, or the window when there is none.
+func scrollContainer(element app.Value) app.Value {
+	window := app.Window()
+	for e := element.Get("parentElement"); e.Truthy(); e = e.Get("parentElement") {
+		switch window.Call("getComputedStyle", e).Get("overflowY").String() {
+		case "auto", "scroll":
+			return e
+		}
+	}
+	return window
+}